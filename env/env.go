@@ -1,12 +1,15 @@
 package env
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
 )
 
 type E struct {
 	Port           string `env:"PORT" envDefault:"8080"`
+	ServerURL      string `env:"SERVER_URL" envDefault:""` // public URL of this server, sent as server_url on webhooks
 	DebugMode      bool   `env:"DEBUG_MODE" envDefault:"false"`
 	DebugWhatsmeow bool   `env:"DEBUG_WHATSMEOW" envDefault:"false"`
 
@@ -26,8 +29,36 @@ type E struct {
 	GCLEnabled   bool   `json:"GCL_ENABLED" envDefault:"false"`
 	GCLProjectID string `json:"GCL_PROJECT_ID"`
 
-	EmitterBufferSize    int `env:"EMITTER_BUFFER_SIZE" envDefault:"2048"`
-	HandlerSemaphoreSize int `env:"HANDLER_SEMAPHORE_SIZE" envDefault:"512"`
+	EmitterBufferSize    int    `env:"EMITTER_BUFFER_SIZE" envDefault:"2048"`
+	EmitterFullPolicy    string `env:"EMITTER_FULL_POLICY" envDefault:"block"` // block, drop-oldest or spill
+	EmitterSpillDir      string `env:"EMITTER_SPILL_DIR" envDefault:"spill"`
+	HandlerSemaphoreSize int    `env:"HANDLER_SEMAPHORE_SIZE" envDefault:"512"`
+
+	WebhookWorkers          int             `env:"WEBHOOK_WORKERS" envDefault:"16"`
+	WebhookPartitionBy      string          `env:"WEBHOOK_PARTITION_BY" envDefault:"instance"`    // instance or chat, events of the same partition are delivered in order
+	WebhookRetrySchedule    []time.Duration `env:"WEBHOOK_RETRY_SCHEDULE" envDefault:"2s,5s,10s"` // delay before each retry, the last failure goes to the dead-letter list
+	WebhookOutboxMaxLen     int64           `env:"WEBHOOK_OUTBOX_MAX_LEN" envDefault:"100000"`
+	WebhookOutboxConsumer   string          `env:"WEBHOOK_OUTBOX_CONSUMER" envDefault:""`         // defaults to hostname
+	WebhookOutboxClaimIdle  time.Duration   `env:"WEBHOOK_OUTBOX_CLAIM_IDLE" envDefault:"5m"`     // pending deliveries older than this are taken over (crash recovery)
+	WebhookDeadLetterMaxLen int64           `env:"WEBHOOK_DEAD_LETTER_MAX_LEN" envDefault:"1000"` // per instance
+	WebhookDeliveryLogLen   int64           `env:"WEBHOOK_DELIVERY_LOG_LEN" envDefault:"500"`     // per instance
+	WebhookBreakerThreshold int             `env:"WEBHOOK_BREAKER_THRESHOLD" envDefault:"5"`      // consecutive failures to open the host circuit, 0 disables it
+	WebhookBreakerCooldown  time.Duration   `env:"WEBHOOK_BREAKER_COOLDOWN" envDefault:"30s"`     // time open before a half-open probe
+	WebhookBreakerMaxPark   time.Duration   `env:"WEBHOOK_BREAKER_MAX_PARK" envDefault:"1h"`      // parked events older than this are dead-lettered
+
+	WebhookSendApikey    bool  `env:"WEBHOOK_SEND_APIKEY" envDefault:"false"`     // sends API_KEY as apikey on Evolution webhooks
+	WebhookReplyMaxBytes int64 `env:"WEBHOOK_REPLY_MAX_BYTES" envDefault:"65536"` // larger webhook responses have their reply actions ignored
+	WebhookReplyWorkers  int   `env:"WEBHOOK_REPLY_WORKERS" envDefault:"32"`      // webhook replies running their actions at the same time
+
+	MessageStatusTTL time.Duration `env:"MESSAGE_STATUS_TTL" envDefault:"168h"` // how long the status timeline of a message is kept
+	ProfileCacheTTL  time.Duration `env:"PROFILE_CACHE_TTL" envDefault:"1h"`    // how long profile pictures and business profiles are cached
+
+	EventSink       string `env:"EVENT_SINK" envDefault:"webhook"` // webhook, nats or amqp, instances can override it
+	EventSinkPrefix string `env:"EVENT_SINK_PREFIX" envDefault:"whatsmiau"`
+	NatsURL         string `env:"NATS_URL" envDefault:""`
+	NatsStream      string `env:"NATS_STREAM" envDefault:"WHATSMIAU"`
+	AmqpURL         string `env:"AMQP_URL" envDefault:""`
+	AmqpExchange    string `env:"AMQP_EXCHANGE" envDefault:"whatsmiau"`
 
 	ProxyAddresses []string `env:"PROXY_ADDRESSES" envDefault:""`      // random choices proxies ex: <SOCKS5|HTTP|HTTPS>://<username>:<password>@<host>:<port>
 	ProxyStrategy  string   `env:"PROXY_STRATEGY" envDefault:"RANDOM"` // todo: implement BALANCED
@@ -46,6 +77,7 @@ type E struct {
 	DefaultSkipOwnMessages bool   `env:"DEFAULT_SKIP_OWN_MESSAGES" envDefault:"false"`
 	DefaultWebhookEvents   string `env:"DEFAULT_WEBHOOK_EVENTS" envDefault:"All"`
 	DefaultWebhookByEvents bool   `env:"DEFAULT_WEBHOOK_BY_EVENTS" envDefault:"false"`
+	DefaultWebhookProfile  string `env:"DEFAULT_WEBHOOK_PROFILE" envDefault:"evolution-v2"` // evolution-v2, evolution-v1 or compact
 }
 
 var Env E
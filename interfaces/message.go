@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+type MessageStatusRepository interface {
+	Add(ctx context.Context, instanceID, messageID string, entry *models.MessageStatusEntry) error
+	Timeline(ctx context.Context, instanceID, messageID string) ([]models.MessageStatusEntry, error)
+}
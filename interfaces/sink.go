@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+// EventSink publishes the instance events on a message broker, as an alternative to webhooks
+type EventSink interface {
+	// Publish sends the event to the subject (NATS) or routing key (AMQP), returning after the broker confirms it
+	Publish(ctx context.Context, subject string, delivery *models.WebhookDelivery) error
+	Close() error
+}
@@ -9,4 +9,6 @@ import (
 type Storage interface {
 	UploadBase64(ctx context.Context, fileName, mimetype, b64 string) (string, error)
 	Upload(ctx context.Context, fileName, mimetype string, file io.Reader) (string, string, error)
+	// Exists reports whether fileName is already stored, returning its public URL when it is
+	Exists(ctx context.Context, fileName string) (string, bool, error)
 }
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+type WebhookOutbox interface {
+	Push(ctx context.Context, delivery *models.WebhookDelivery) error
+	Read(ctx context.Context, consumer string, count int64, block time.Duration) ([]models.WebhookDelivery, error)
+	Claim(ctx context.Context, consumer string, minIdle time.Duration, count int64) ([]models.WebhookDelivery, error)
+	// ReadPending returns the deliveries read by the consumer and not acked yet, after the given stream id
+	ReadPending(ctx context.Context, consumer string, after string, count int64) ([]models.WebhookDelivery, error)
+	// Touch resets the idle time of deliveries the consumer still holds, so they are not claimed by other replicas
+	Touch(ctx context.Context, consumer string, streamIDs []string) error
+	Ack(ctx context.Context, delivery *models.WebhookDelivery) error
+	DeadLetter(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDeadLetters(ctx context.Context, instanceID string, limit int64) ([]models.WebhookDelivery, error)
+	ReplayDeadLetters(ctx context.Context, instanceID string, ids []string) (int, error)
+	PurgeDeadLetters(ctx context.Context, instanceID string) (int64, error)
+	// Park moves the delivery to the retry queue until the given time
+	Park(ctx context.Context, delivery *models.WebhookDelivery, until time.Time) error
+	// Unpark pushes back to the outbox the parked deliveries that are due
+	Unpark(ctx context.Context, now time.Time, count int64) (int, error)
+}
+
+type WebhookDeliveryLog interface {
+	Add(ctx context.Context, instanceID string, log *models.WebhookDeliveryLog) error
+	List(ctx context.Context, instanceID string, limit int64) ([]models.WebhookDeliveryLog, error)
+}
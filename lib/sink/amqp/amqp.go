@@ -0,0 +1,117 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+var _ interfaces.EventSink = (*Amqp)(nil)
+
+var ErrNotConfirmed = errors.New("message not confirmed by the broker")
+
+type Amqp struct {
+	url      string
+	exchange string
+
+	mu      sync.Mutex
+	conn    *amqp091.Connection
+	channel *amqp091.Channel
+}
+
+// New connects to the broker and declares a durable topic exchange, events are routed by <prefix>.<instance>.<event>
+func New(url, exchange string) (*Amqp, error) {
+	s := &Amqp{
+		url:      url,
+		exchange: exchange,
+	}
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Amqp) connect() error {
+	conn, err := amqp091.Dial(s.url)
+	if err != nil {
+		return err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := channel.ExchangeDeclare(s.exchange, amqp091.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.conn = conn
+	s.channel = channel
+	return nil
+}
+
+func (s *Amqp) Publish(ctx context.Context, routingKey string, delivery *models.WebhookDelivery) error {
+	// a channel is not safe for concurrent publishing with confirms
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil || s.conn.IsClosed() || s.channel.IsClosed() {
+		if s.conn != nil {
+			s.conn.Close()
+		}
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	confirmation, err := s.channel.PublishWithDeferredConfirmWithContext(ctx, s.exchange, routingKey, false, false, amqp091.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp091.Persistent,
+		MessageId:    delivery.ID,
+		Timestamp:    time.Now(),
+		Type:         delivery.Event,
+		Headers: amqp091.Table{
+			"instance": delivery.InstanceID,
+		},
+		Body: delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	ok, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotConfirmed
+	}
+
+	return nil
+}
+
+func (s *Amqp) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.Close()
+}
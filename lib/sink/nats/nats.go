@@ -0,0 +1,76 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	natsio "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+var _ interfaces.EventSink = (*Nats)(nil)
+
+type Nats struct {
+	conn *natsio.Conn
+	js   jetstream.JetStream
+}
+
+// New connects to NATS and makes sure the JetStream stream exists, capturing every subject under prefix
+func New(url, stream, prefix string) (*Nats, error) {
+	conn, err := natsio.Connect(url,
+		natsio.Name("whatsmiau"),
+		natsio.MaxReconnects(-1),
+		natsio.ReconnectWait(2*time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+	defer c()
+
+	if _, err := js.Stream(ctx, stream); errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     stream,
+			Subjects: []string{prefix + ".>"},
+			Storage:  jetstream.FileStorage,
+		})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Nats{
+		conn: conn,
+		js:   js,
+	}, nil
+}
+
+func (s *Nats) Publish(ctx context.Context, subject string, delivery *models.WebhookDelivery) error {
+	msg := natsio.NewMsg(subject)
+	msg.Data = delivery.Payload
+	msg.Header.Set("Content-Type", "application/json")
+	msg.Header.Set("X-Whatsmiau-Instance", delivery.InstanceID)
+	msg.Header.Set("X-Whatsmiau-Event", delivery.Event)
+
+	// the delivery id makes JetStream drop duplicates when a publish is retried
+	_, err := s.js.PublishMsg(ctx, msg, jetstream.WithMsgID(delivery.ID))
+	return err
+}
+
+func (s *Nats) Close() error {
+	return s.conn.Drain()
+}
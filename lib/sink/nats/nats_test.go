@@ -0,0 +1,187 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsio "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+// runServer starts an in-process NATS server with JetStream enabled
+func runServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	return srv
+}
+
+func jetStream(t *testing.T, srv *server.Server) jetstream.JetStream {
+	t.Helper()
+
+	conn, err := natsio.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("failed to create jetstream: %v", err)
+	}
+
+	return js
+}
+
+func TestNewCreatesStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    *jetstream.StreamConfig
+		wantStorage jetstream.StorageType
+		wantSubject string
+	}{
+		{
+			name:        "missing stream is created",
+			wantStorage: jetstream.FileStorage,
+			wantSubject: "whatsmiau.>",
+		},
+		{
+			name:        "existing stream is kept",
+			existing:    &jetstream.StreamConfig{Name: "WHATSMIAU", Subjects: []string{"events.>"}, Storage: jetstream.MemoryStorage},
+			wantStorage: jetstream.MemoryStorage,
+			wantSubject: "events.>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := runServer(t)
+			js := jetStream(t, srv)
+
+			ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+			defer c()
+
+			if tt.existing != nil {
+				if _, err := js.CreateStream(ctx, *tt.existing); err != nil {
+					t.Fatalf("failed to create existing stream: %v", err)
+				}
+			}
+
+			sink, err := New(srv.ClientURL(), "WHATSMIAU", "whatsmiau")
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer sink.Close()
+
+			stream, err := js.Stream(ctx, "WHATSMIAU")
+			if err != nil {
+				t.Fatalf("stream WHATSMIAU not found: %v", err)
+			}
+			config := stream.CachedInfo().Config
+			if len(config.Subjects) != 1 || config.Subjects[0] != tt.wantSubject {
+				t.Errorf("stream subjects = %v, want [%s]", config.Subjects, tt.wantSubject)
+			}
+			if config.Storage != tt.wantStorage {
+				t.Errorf("stream storage = %s, want %s", config.Storage, tt.wantStorage)
+			}
+		})
+	}
+}
+
+func TestNewFailsWithoutJetStream(t *testing.T) {
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	defer srv.Shutdown()
+
+	if sink, err := New(srv.ClientURL(), "WHATSMIAU", "whatsmiau"); err == nil {
+		sink.Close()
+		t.Fatal("New() without JetStream should fail")
+	}
+}
+
+func TestPublish(t *testing.T) {
+	srv := runServer(t)
+
+	sink, err := New(srv.ClientURL(), "WHATSMIAU", "whatsmiau")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer sink.Close()
+
+	deliveries := []*models.WebhookDelivery{
+		{ID: "delivery-1", InstanceID: "instance-1", Event: "messages.upsert", Payload: []byte(`{"n":1}`)},
+		{ID: "delivery-2", InstanceID: "instance-1", Event: "messages.update", Payload: []byte(`{"n":2}`)},
+		// a retried publish keeps the delivery id and is dropped by the stream
+		{ID: "delivery-1", InstanceID: "instance-1", Event: "messages.upsert", Payload: []byte(`{"n":1}`)},
+	}
+
+	ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+	defer c()
+
+	for _, delivery := range deliveries {
+		if err := sink.Publish(ctx, "whatsmiau.instance-1."+delivery.Event, delivery); err != nil {
+			t.Fatalf("Publish(%s) error = %v", delivery.ID, err)
+		}
+	}
+
+	js := jetStream(t, srv)
+	stream, err := js.Stream(ctx, "WHATSMIAU")
+	if err != nil {
+		t.Fatalf("stream WHATSMIAU not found: %v", err)
+	}
+	if msgs := stream.CachedInfo().State.Msgs; msgs != 2 {
+		t.Fatalf("stored messages = %d, want 2", msgs)
+	}
+
+	for i, delivery := range deliveries[:2] {
+		message, err := stream.GetMsg(ctx, uint64(i+1))
+		if err != nil {
+			t.Fatalf("failed to get message %d: %v", i+1, err)
+		}
+
+		if want := "whatsmiau.instance-1." + delivery.Event; message.Subject != want {
+			t.Errorf("message %d subject = %q, want %q", i, message.Subject, want)
+		}
+		if string(message.Data) != string(delivery.Payload) {
+			t.Errorf("message %d data = %s, want %s", i, message.Data, delivery.Payload)
+		}
+
+		headers := map[string]string{
+			"Content-Type":         "application/json",
+			"X-Whatsmiau-Instance": delivery.InstanceID,
+			"X-Whatsmiau-Event":    delivery.Event,
+			"Nats-Msg-Id":          delivery.ID,
+		}
+		for key, want := range headers {
+			if got := message.Header.Get(key); got != want {
+				t.Errorf("message %d header %s = %q, want %q", i, key, got, want)
+			}
+		}
+	}
+}
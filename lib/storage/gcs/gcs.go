@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -103,9 +104,25 @@ func (s *Gcs) Upload(ctx context.Context, fileName, mimetype string, file io.Rea
 		return "", "", err
 	}
 
+	return s.url(obj), fileName, nil
+}
+
+func (s *Gcs) Exists(ctx context.Context, fileName string) (string, bool, error) {
+	obj := s.googleBucket.Object(fileName)
+	if _, err := obj.Attrs(ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return s.url(obj), true, nil
+}
+
+func (s *Gcs) url(obj *storage.ObjectHandle) string {
 	return fmt.Sprintf("%s/%s/%s",
 		env.Env.GCSURL,
 		obj.BucketName(),
-		fileName,
-	), fileName, nil
+		obj.ObjectName(),
+	)
 }
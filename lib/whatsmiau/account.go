@@ -0,0 +1,230 @@
+package whatsmiau
+
+import (
+	"fmt"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+// FetchProfile returns the profile of jid or, when jid is nil, of the connected number
+func (s *Whatsmiau) FetchProfile(ctx context.Context, instanceID string, jid *types.JID) (*models.Profile, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	own := jid == nil
+	if own {
+		if client.Store.ID == nil {
+			return nil, whatsmeow.ErrNotLoggedIn
+		}
+		ownJID := client.Store.ID.ToNonAD()
+		jid = &ownJID
+	}
+
+	infos, err := client.GetUserInfo(ctx, []types.JID{*jid})
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := infos[*jid]
+	if !ok {
+		return nil, fmt.Errorf("profile not found: %s", jid.String())
+	}
+
+	result := &models.Profile{
+		Wuid:       jid.String(),
+		Status:     info.Status,
+		PictureID:  info.PictureID,
+		IsBusiness: info.VerifiedName != nil,
+	}
+
+	if own {
+		result.Name = client.Store.PushName
+	} else if contact, err := client.Store.Contacts.GetContact(ctx, *jid); err == nil {
+		switch {
+		case contact.FullName != "":
+			result.Name = contact.FullName
+		case contact.BusinessName != "":
+			result.Name = contact.BusinessName
+		default:
+			result.Name = contact.PushName
+		}
+	}
+
+	if picture, err := s.profilePicture(ctx, instanceID, *jid, false); err == nil && picture.URL != "" {
+		result.Picture = picture.URL
+		result.PictureID = picture.ID
+	}
+
+	return result, nil
+}
+
+// UpdateProfileName changes the push name of the connected number
+func (s *Whatsmiau) UpdateProfileName(ctx context.Context, instanceID, name string) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildSettingPushName(name)); err != nil {
+		return err
+	}
+
+	// the patch is only applied locally on the next app state sync, so the store is updated right away
+	client.Store.PushName = name
+	if err := client.Store.Save(ctx); err != nil {
+		zap.L().Error("failed to save push name", zap.String("instance", instanceID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// UpdateProfileStatus changes the about text of the connected number
+func (s *Whatsmiau) UpdateProfileStatus(ctx context.Context, instanceID, status string) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	return client.SetStatusMessage(ctx, status)
+}
+
+// UpdateProfilePicture changes the picture of the connected number, cropped and resized as WhatsApp expects.
+// The picture is an URL or base64 data URI and the new picture ID is returned.
+func (s *Whatsmiau) UpdateProfilePicture(ctx context.Context, instanceID, picture string) (string, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return "", whatsmeow.ErrClientIsNil
+	}
+
+	data, err := s.loadMedia(ctx, picture)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := resizeProfilePicture(data)
+	if err != nil {
+		return "", err
+	}
+
+	// an empty target changes the picture of the connected number
+	pictureID, err := client.SetGroupPhoto(ctx, types.EmptyJID, image)
+	if err != nil {
+		return "", err
+	}
+
+	s.forgetOwnPicture(instanceID, client)
+	return pictureID, nil
+}
+
+func (s *Whatsmiau) RemoveProfilePicture(ctx context.Context, instanceID string) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	if _, err := client.SetGroupPhoto(ctx, types.EmptyJID, nil); err != nil {
+		return err
+	}
+
+	s.forgetOwnPicture(instanceID, client)
+	return nil
+}
+
+func (s *Whatsmiau) forgetOwnPicture(instanceID string, client *whatsmeow.Client) {
+	if client.Store.ID != nil {
+		s.forgetPicture(instanceID, client.Store.ID.ToNonAD())
+	}
+}
+
+// FetchBusinessProfile returns the business profile of jid, cached for PROFILE_CACHE_TTL. The query is sent
+// directly because whatsmeow's GetBusinessProfile does not parse the description and websites.
+func (s *Whatsmiau) FetchBusinessProfile(ctx context.Context, instanceID string, jid types.JID) (*models.BusinessProfile, error) {
+	key := fmt.Sprintf("business_%s_%s", instanceID, jid.ToNonAD().String())
+	if cached, ok := s.pictures.Get(key); ok {
+		return cached.(*models.BusinessProfile), nil
+	}
+
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz",
+		Type:      "get",
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:   "business_profile",
+			Attrs: waBinary.Attrs{"v": "244"},
+			Content: []waBinary.Node{{
+				Tag:   "profile",
+				Attrs: waBinary.Attrs{"jid": jid},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BusinessProfile{Wuid: jid.ToNonAD().String()}
+	profile, ok := resp.GetOptionalChildByTag("business_profile", "profile")
+	if ok {
+		if _, ok := profile.AttrGetter().GetJID("jid", false); ok {
+			parseBusinessProfile(&profile, result)
+		}
+	}
+
+	s.pictures.Set(key, result, env.Env.ProfileCacheTTL)
+	return result, nil
+}
+
+func parseBusinessProfile(profile *waBinary.Node, result *models.BusinessProfile) {
+	result.IsBusiness = true
+	for _, child := range profile.GetChildren() {
+		content, _ := child.Content.([]byte)
+		switch child.Tag {
+		case "description":
+			result.Description = string(content)
+		case "email":
+			result.Email = string(content)
+		case "address":
+			result.Address = string(content)
+		case "website":
+			result.Website = append(result.Website, string(content))
+		case "categories":
+			for _, category := range child.GetChildren() {
+				if name, ok := category.Content.([]byte); ok && category.Tag == "category" {
+					result.Categories = append(result.Categories, string(name))
+				}
+			}
+		case "business_hours":
+			hours := &models.BusinessHours{TimeZone: child.AttrGetter().OptionalString("timezone")}
+			for _, config := range child.GetChildren() {
+				if config.Tag != "business_hours_config" {
+					continue
+				}
+				attrs := config.AttrGetter()
+				hours.Config = append(hours.Config, models.BusinessHoursEntry{
+					DayOfWeek: attrs.OptionalString("day_of_week"),
+					Mode:      attrs.OptionalString("mode"),
+					OpenTime:  attrs.OptionalString("open_time"),
+					CloseTime: attrs.OptionalString("close_time"),
+				})
+			}
+			result.BusinessHours = hours
+		}
+	}
+
+	if len(result.Categories) > 0 {
+		result.Category = result.Categories[0]
+	}
+}
@@ -0,0 +1,138 @@
+package whatsmiau
+
+import (
+	"bytes"
+	"compress/gzip"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.uber.org/zap"
+)
+
+const batchTick = 50 * time.Millisecond
+
+type webhookBatch struct {
+	deliveries []*models.WebhookDelivery
+	deadline   time.Time
+}
+
+// webhookBatches accumulates, inside a delivery worker, the events of webhooks with batch enabled.
+// As a partition always goes to the same worker, the events keep their order inside the batch.
+type webhookBatches struct {
+	pending map[string]*webhookBatch
+}
+
+func newWebhookBatches() *webhookBatches {
+	return &webhookBatches{pending: make(map[string]*webhookBatch)}
+}
+
+func batchKey(delivery *models.WebhookDelivery) string {
+	return delivery.InstanceID + "|" + delivery.WebhookID + "|" + delivery.URL
+}
+
+// add returns the batch when it reaches its size
+func (b *webhookBatches) add(delivery *models.WebhookDelivery) []*models.WebhookDelivery {
+	key := batchKey(delivery)
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &webhookBatch{
+			deadline: time.Now().Add(time.Duration(delivery.Batch.Interval) * time.Millisecond),
+		}
+		b.pending[key] = batch
+	}
+
+	batch.deliveries = append(batch.deliveries, delivery)
+	if len(batch.deliveries) < delivery.Batch.Size {
+		return nil
+	}
+
+	delete(b.pending, key)
+	return batch.deliveries
+}
+
+// due returns the batches whose interval elapsed
+func (b *webhookBatches) due(now time.Time) [][]*models.WebhookDelivery {
+	var result [][]*models.WebhookDelivery
+	for key, batch := range b.pending {
+		if now.Before(batch.deadline) {
+			continue
+		}
+
+		result = append(result, batch.deliveries)
+		delete(b.pending, key)
+	}
+
+	return result
+}
+
+// processBatch sends the events as a single JSON array. The batch is retried as a whole, and each event
+// is acked, parked or dead-lettered on its own, so replays keep working per event.
+func (s *Whatsmiau) processBatch(deliveries []*models.WebhookDelivery) {
+	first := deliveries[0]
+	s.webhookCredentials(first)
+
+	headers := make(map[string]string, len(first.Headers)+1)
+	for key, value := range first.Headers {
+		headers[key] = value
+	}
+
+	body, compressed := encodeBatch(deliveries, first.Batch.Gzip)
+	if compressed {
+		headers["Content-Encoding"] = "gzip"
+	}
+
+	batch := &models.WebhookDelivery{
+		ID:         uuid.NewString(),
+		InstanceID: first.InstanceID,
+		WebhookID:  first.WebhookID,
+		Event:      "batch",
+		URL:        first.URL,
+		Secret:     first.Secret,
+		Headers:    headers,
+		Payload:    body,
+		CreatedAt:  time.Now(),
+	}
+
+	// one response answers every event of the batch, so reply actions are not run
+	_, sendErr := s.sendWebhookWithRetry(batch)
+	for _, delivery := range deliveries {
+		delivery.Attempts += batch.Attempts
+		delivery.StatusCode = batch.StatusCode
+		delivery.LatencyMs = batch.LatencyMs
+		delivery.Response = batch.Response
+
+		s.finishDelivery(delivery, sendErr)
+		s.inflight.Delete(delivery.StreamID)
+	}
+}
+
+// encodeBatch joins the payloads as a JSON array, gzipped when asked. A failed compression sends it uncompressed.
+func encodeBatch(deliveries []*models.WebhookDelivery, compress bool) ([]byte, bool) {
+	var payload bytes.Buffer
+	payload.WriteByte('[')
+	for i, delivery := range deliveries {
+		if i > 0 {
+			payload.WriteByte(',')
+		}
+		payload.Write(delivery.Payload)
+	}
+	payload.WriteByte(']')
+
+	if !compress {
+		return payload.Bytes(), false
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(payload.Bytes()); err != nil {
+		zap.L().Error("failed to compress webhook batch", zap.Error(err))
+		return payload.Bytes(), false
+	}
+	if err := writer.Close(); err != nil {
+		zap.L().Error("failed to compress webhook batch", zap.Error(err))
+		return payload.Bytes(), false
+	}
+
+	return compressed.Bytes(), true
+}
@@ -0,0 +1,136 @@
+package whatsmiau
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+func batchDelivery(instanceID, webhookID, payload string, batch *models.WebhookBatch) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		InstanceID: instanceID,
+		WebhookID:  webhookID,
+		URL:        "https://receiver.example.com/" + webhookID,
+		Payload:    []byte(payload),
+		Batch:      batch,
+	}
+}
+
+func TestWebhookBatchesAdd(t *testing.T) {
+	batch := &models.WebhookBatch{Size: 3, Interval: 60000}
+
+	tests := []struct {
+		name      string
+		instances []string // instance of each added delivery
+		wantFull  []int    // size of the batch returned by each add, 0 when none
+	}{
+		{name: "returns when the size is reached", instances: []string{"a", "a", "a"}, wantFull: []int{0, 0, 3}},
+		{name: "starts a new batch after a full one", instances: []string{"a", "a", "a", "a"}, wantFull: []int{0, 0, 3, 0}},
+		{name: "keeps instances apart", instances: []string{"a", "b", "a", "b", "a"}, wantFull: []int{0, 0, 0, 0, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := newWebhookBatches()
+			for i, instanceID := range tt.instances {
+				full := batches.add(batchDelivery(instanceID, "main", `{"n":1}`, batch))
+				if len(full) != tt.wantFull[i] {
+					t.Fatalf("add %d returned %d deliveries, want %d", i, len(full), tt.wantFull[i])
+				}
+				for _, delivery := range full {
+					if delivery.InstanceID != instanceID {
+						t.Fatalf("add %d returned a delivery of %q, want %q", i, delivery.InstanceID, instanceID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookBatchesDue(t *testing.T) {
+	batches := newWebhookBatches()
+	fast := &models.WebhookBatch{Size: 10, Interval: 100}
+	slow := &models.WebhookBatch{Size: 10, Interval: 60000}
+
+	start := time.Now()
+	batches.add(batchDelivery("a", "fast", `{"n":1}`, fast))
+	batches.add(batchDelivery("a", "fast", `{"n":2}`, fast))
+	batches.add(batchDelivery("a", "slow", `{"n":3}`, slow))
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		sizes []int
+	}{
+		{name: "nothing before the interval", now: start, sizes: nil},
+		{name: "the elapsed batch in order", now: start.Add(time.Second), sizes: []int{2}},
+		{name: "a due batch is returned once", now: start.Add(2 * time.Second), sizes: nil},
+		{name: "the slow batch after its interval", now: start.Add(2 * time.Minute), sizes: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due := batches.due(tt.now)
+			if len(due) != len(tt.sizes) {
+				t.Fatalf("due() returned %d batches, want %d", len(due), len(tt.sizes))
+			}
+			for i, deliveries := range due {
+				if len(deliveries) != tt.sizes[i] {
+					t.Fatalf("batch %d has %d deliveries, want %d", i, len(deliveries), tt.sizes[i])
+				}
+			}
+			if len(due) == 1 && len(due[0]) == 2 && (string(due[0][0].Payload) != `{"n":1}` || string(due[0][1].Payload) != `{"n":2}`) {
+				t.Fatalf("batch out of order: %s, %s", due[0][0].Payload, due[0][1].Payload)
+			}
+		})
+	}
+}
+
+func TestEncodeBatch(t *testing.T) {
+	deliveries := []*models.WebhookDelivery{
+		{Payload: []byte(`{"event":"messages.upsert"}`)},
+		{Payload: []byte(`{"event":"messages.update"}`)},
+	}
+
+	tests := []struct {
+		name       string
+		deliveries []*models.WebhookDelivery
+		compress   bool
+		want       string
+	}{
+		{name: "single event", deliveries: deliveries[:1], want: `[{"event":"messages.upsert"}]`},
+		{name: "events in order", deliveries: deliveries, want: `[{"event":"messages.upsert"},{"event":"messages.update"}]`},
+		{name: "gzip", deliveries: deliveries, compress: true, want: `[{"event":"messages.upsert"},{"event":"messages.update"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, compressed := encodeBatch(tt.deliveries, tt.compress)
+			if compressed != tt.compress {
+				t.Fatalf("encodeBatch() compressed = %t, want %t", compressed, tt.compress)
+			}
+
+			if compressed {
+				reader, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("body is not gzip: %v", err)
+				}
+				if body, err = io.ReadAll(reader); err != nil {
+					t.Fatalf("failed to decompress body: %v", err)
+				}
+			}
+
+			if string(body) != tt.want {
+				t.Errorf("encodeBatch() = %s, want %s", body, tt.want)
+			}
+			if !json.Valid(body) {
+				t.Errorf("encodeBatch() is not valid JSON: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package whatsmiau
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.uber.org/zap"
+)
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// errCircuitOpen is returned when the webhook host is failing, the delivery is parked instead of retried
+var errCircuitOpen = errors.New("circuit breaker open for webhook host")
+
+// hostBreaker opens after WEBHOOK_BREAKER_THRESHOLD consecutive failures of a host. After
+// WEBHOOK_BREAKER_COOLDOWN it lets a single probe through (half-open): a success closes it, a failure opens it again.
+type hostBreaker struct {
+	mu          sync.Mutex
+	host        string
+	state       string
+	failures    int
+	openedAt    time.Time
+	probing     bool
+	transitions int64
+}
+
+type circuitBreakers struct {
+	hosts *xsync.Map[string, *hostBreaker]
+}
+
+func newCircuitBreakers() *circuitBreakers {
+	return &circuitBreakers{hosts: xsync.NewMap[string, *hostBreaker]()}
+}
+
+func webhookHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	return u.Host
+}
+
+func (b *circuitBreakers) get(host string) *hostBreaker {
+	breaker, _ := b.hosts.LoadOrCompute(host, func() (*hostBreaker, bool) {
+		return &hostBreaker{host: host, state: BreakerClosed}, false
+	})
+
+	return breaker
+}
+
+// Allow reports if a request to the host can be made
+func (b *circuitBreakers) Allow(host string) bool {
+	if env.Env.WebhookBreakerThreshold <= 0 {
+		return true
+	}
+
+	breaker := b.get(host)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.state {
+	case BreakerOpen:
+		if time.Since(breaker.openedAt) < env.Env.WebhookBreakerCooldown {
+			return false
+		}
+		breaker.setState(BreakerHalfOpen)
+		breaker.probing = true
+		return true
+	case BreakerHalfOpen:
+		if breaker.probing {
+			return false
+		}
+		breaker.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record feeds the result of a request to the host
+func (b *circuitBreakers) Record(host string, success bool) {
+	if env.Env.WebhookBreakerThreshold <= 0 {
+		return
+	}
+
+	breaker := b.get(host)
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+	if success {
+		breaker.failures = 0
+		breaker.setState(BreakerClosed)
+		return
+	}
+
+	breaker.failures++
+	if breaker.state == BreakerHalfOpen || breaker.failures >= env.Env.WebhookBreakerThreshold {
+		breaker.openedAt = time.Now()
+		breaker.setState(BreakerOpen)
+	}
+}
+
+func (b *circuitBreakers) List() []models.WebhookBreaker {
+	result := []models.WebhookBreaker{}
+	b.hosts.Range(func(host string, breaker *hostBreaker) bool {
+		breaker.mu.Lock()
+		status := models.WebhookBreaker{
+			Host:        host,
+			State:       breaker.state,
+			Failures:    breaker.failures,
+			Transitions: breaker.transitions,
+		}
+		if breaker.state != BreakerClosed {
+			openedAt := breaker.openedAt
+			status.OpenedAt = &openedAt
+		}
+		breaker.mu.Unlock()
+
+		result = append(result, status)
+		return true
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host < result[j].Host
+	})
+
+	return result
+}
+
+// setState must be called with the lock held, state changes are logged as alerts
+func (h *hostBreaker) setState(state string) {
+	if h.state == state {
+		return
+	}
+
+	from := h.state
+	h.state = state
+	h.transitions++
+
+	fields := []zap.Field{
+		zap.String("host", h.host),
+		zap.String("from", from),
+		zap.String("to", state),
+		zap.Int("failures", h.failures),
+		zap.Int64("transitions", h.transitions),
+	}
+	if state == BreakerOpen {
+		zap.L().Error("webhook circuit breaker opened", fields...)
+	} else {
+		zap.L().Warn("webhook circuit breaker state changed", fields...)
+	}
+}
+
+// CircuitBreakers returns the state of the circuit breaker of every webhook host seen by this process
+func (s *Whatsmiau) CircuitBreakers() []models.WebhookBreaker {
+	return s.breakers.List()
+}
+
+// CircuitBreakerSummary counts the webhook hosts by circuit breaker state, listing the ones not closed
+func (s *Whatsmiau) CircuitBreakerSummary() models.WebhookBreakerSummary {
+	summary := models.WebhookBreakerSummary{Failing: []models.WebhookBreaker{}}
+	for _, breaker := range s.breakers.List() {
+		summary.Hosts++
+		summary.Transitions += breaker.Transitions
+
+		switch breaker.State {
+		case BreakerOpen:
+			summary.Open++
+		case BreakerHalfOpen:
+			summary.HalfOpen++
+		default:
+			continue
+		}
+		summary.Failing = append(summary.Failing, breaker)
+	}
+
+	return summary
+}
@@ -0,0 +1,178 @@
+package whatsmiau
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+)
+
+const breakerTestHost = "receiver.example.com"
+
+type breakerStep struct {
+	op        string // allow, success, failure or cooldown (moves the opening past WEBHOOK_BREAKER_COOLDOWN)
+	wantAllow bool   // allow only
+	wantState string
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookBreakerCooldown = time.Minute
+
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []breakerStep
+	}{
+		{
+			name:      "failures below the threshold keep it closed",
+			threshold: 3,
+			steps: []breakerStep{
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "allow", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name:      "a success resets the failures",
+			threshold: 3,
+			steps: []breakerStep{
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "success", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerOpen},
+			},
+		},
+		{
+			name:      "open rejects until the cooldown",
+			threshold: 2,
+			steps: []breakerStep{
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerOpen},
+				{op: "allow", wantAllow: false, wantState: BreakerOpen},
+				{op: "allow", wantAllow: false, wantState: BreakerOpen},
+			},
+		},
+		{
+			name:      "a single probe after the cooldown closes it on success",
+			threshold: 1,
+			steps: []breakerStep{
+				{op: "failure", wantState: BreakerOpen},
+				{op: "cooldown", wantState: BreakerOpen},
+				{op: "allow", wantAllow: true, wantState: BreakerHalfOpen},
+				{op: "allow", wantAllow: false, wantState: BreakerHalfOpen},
+				{op: "success", wantState: BreakerClosed},
+				{op: "allow", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name:      "a failed probe opens it again",
+			threshold: 5,
+			steps: []breakerStep{
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerClosed},
+				{op: "failure", wantState: BreakerOpen},
+				{op: "cooldown", wantState: BreakerOpen},
+				{op: "allow", wantAllow: true, wantState: BreakerHalfOpen},
+				{op: "failure", wantState: BreakerOpen},
+				{op: "allow", wantAllow: false, wantState: BreakerOpen},
+			},
+		},
+		{
+			name:      "a zero threshold disables it",
+			threshold: 0,
+			steps: []breakerStep{
+				{op: "failure", wantState: ""},
+				{op: "failure", wantState: ""},
+				{op: "allow", wantAllow: true, wantState: ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.Env.WebhookBreakerThreshold = tt.threshold
+			breakers := newCircuitBreakers()
+
+			for i, step := range tt.steps {
+				switch step.op {
+				case "allow":
+					if got := breakers.Allow(breakerTestHost); got != step.wantAllow {
+						t.Fatalf("step %d: Allow() = %t, want %t", i, got, step.wantAllow)
+					}
+				case "success", "failure":
+					breakers.Record(breakerTestHost, step.op == "success")
+				case "cooldown":
+					breaker := breakers.get(breakerTestHost)
+					breaker.mu.Lock()
+					breaker.openedAt = breaker.openedAt.Add(-env.Env.WebhookBreakerCooldown)
+					breaker.mu.Unlock()
+				}
+
+				if got := breakerState(breakers); got != step.wantState {
+					t.Fatalf("step %d (%s): state = %q, want %q", i, step.op, got, step.wantState)
+				}
+			}
+		})
+	}
+}
+
+// breakerState returns the listed state of the test host, empty when it was never seen
+func breakerState(breakers *circuitBreakers) string {
+	for _, breaker := range breakers.List() {
+		if breaker.Host == breakerTestHost {
+			return breaker.State
+		}
+	}
+
+	return ""
+}
+
+func TestCircuitBreakerSummary(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookBreakerThreshold = 1
+	env.Env.WebhookBreakerCooldown = time.Minute
+
+	s := &Whatsmiau{breakers: newCircuitBreakers()}
+	s.breakers.Record("a.example.com", true)
+	s.breakers.Record("b.example.com", false)
+	s.breakers.Record("c.example.com", false)
+	s.breakers.get("c.example.com").openedAt = time.Now().Add(-time.Hour)
+	s.breakers.Allow("c.example.com")
+
+	summary := s.CircuitBreakerSummary()
+	if summary.Hosts != 3 || summary.Open != 1 || summary.HalfOpen != 1 || summary.Transitions != 3 {
+		t.Fatalf("summary = %+v, want 3 hosts, 1 open, 1 half-open and 3 transitions", summary)
+	}
+	if len(summary.Failing) != 2 || summary.Failing[0].Host != "b.example.com" || summary.Failing[1].Host != "c.example.com" {
+		t.Fatalf("failing = %+v, want b.example.com and c.example.com", summary.Failing)
+	}
+	if summary.Failing[0].OpenedAt == nil {
+		t.Errorf("open breaker without openedAt")
+	}
+}
+
+func TestWebhookHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://receiver.example.com/webhook", want: "receiver.example.com"},
+		{url: "http://receiver.example.com:8080/a?b=c", want: "receiver.example.com:8080"},
+		{url: "receiver", want: "receiver"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := webhookHost(tt.url); got != tt.want {
+				t.Errorf("webhookHost(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package whatsmiau
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+type CreateCommunityRequest struct {
+	InstanceID  string `json:"instance_id"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// CreateCommunity creates a community, the server creates its announcement group
+func (s *Whatsmiau) CreateCommunity(ctx context.Context, data *CreateCommunityRequest) (*models.GroupInfo, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	req := whatsmeow.ReqCreateGroup{
+		Name: data.Subject,
+	}
+	req.IsParent = true
+
+	info, err := client.CreateGroup(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data.Description) > 0 {
+		if err := client.SetGroupDescription(ctx, info.JID, data.Description); err != nil {
+			zap.L().Error("failed to set community description", zap.String("instance", data.InstanceID), zap.String("community", info.JID.String()), zap.Error(err))
+		} else {
+			info.Topic = data.Description
+		}
+	}
+
+	return s.convertGroupMetadata(ctx, data.InstanceID, info, true), nil
+}
+
+type LinkCommunityGroupRequest struct {
+	InstanceID   string     `json:"instance_id"`
+	CommunityJID *types.JID `json:"community_jid"`
+	GroupJID     *types.JID `json:"group_jid"`
+	Unlink       bool       `json:"unlink"`
+}
+
+// LinkCommunityGroup links an existing group to the community, or unlinks it
+func (s *Whatsmiau) LinkCommunityGroup(ctx context.Context, data *LinkCommunityGroupRequest) error {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	if data.Unlink {
+		return client.UnlinkGroup(ctx, *data.CommunityJID, *data.GroupJID)
+	}
+
+	return client.LinkGroup(ctx, *data.CommunityJID, *data.GroupJID)
+}
+
+func (s *Whatsmiau) CommunitySubGroups(ctx context.Context, instanceID string, community types.JID) ([]models.CommunitySubGroup, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	groups, err := client.GetSubGroups(ctx, community)
+	if err != nil {
+		return nil, err
+	}
+
+	s.communities.Store(community.String(), true)
+
+	result := make([]models.CommunitySubGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, models.CommunitySubGroup{
+			ID:                  group.JID.String(),
+			Subject:             group.Name,
+			IsCommunityAnnounce: group.IsDefaultSubGroup,
+		})
+	}
+
+	return result, nil
+}
+
+// CommunityAnnouncementGroup returns the announcement group of the community, the one messages to all members go to
+func (s *Whatsmiau) CommunityAnnouncementGroup(ctx context.Context, instanceID string, community types.JID) (*types.JID, error) {
+	groups, err := s.CommunitySubGroups(ctx, instanceID, community)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if !group.IsCommunityAnnounce {
+			continue
+		}
+
+		jid, err := types.ParseJID(group.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jid, nil
+	}
+
+	return nil, fmt.Errorf("community %s has no announcement group", community.String())
+}
+
+type SendCommunityAnnouncementRequest struct {
+	InstanceID   string     `json:"instance_id"`
+	CommunityJID *types.JID `json:"community_jid"`
+	Text         string     `json:"text"`
+}
+
+type SendCommunityAnnouncementResponse struct {
+	ID        string    `json:"id"`
+	GroupJID  types.JID `json:"group_jid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SendCommunityAnnouncement sends a text to the announcement group of the community
+func (s *Whatsmiau) SendCommunityAnnouncement(ctx context.Context, data *SendCommunityAnnouncementRequest) (*SendCommunityAnnouncementResponse, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	group, err := s.CommunityAnnouncementGroup(ctx, data.InstanceID, *data.CommunityJID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &waE2E.Message{
+		Conversation: &data.Text,
+	}
+	res, err := client.SendMessage(ctx, *group, message)
+	if err != nil {
+		go s.handleSendError(data.InstanceID, *group, res)
+		return nil, err
+	}
+
+	go s.handleSentMessage(data.InstanceID, *group, res, message)
+
+	return &SendCommunityAnnouncementResponse{
+		ID:        res.ID,
+		GroupJID:  *group,
+		CreatedAt: res.Timestamp,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/emersion/go-vcard"
 	"github.com/google/uuid"
 	cache "github.com/patrickmn/go-cache"
+	"github.com/verbeux-ai/whatsmiau/env"
 	"github.com/verbeux-ai/whatsmiau/models"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
@@ -25,11 +27,6 @@ import (
 	"golang.org/x/net/context"
 )
 
-type emitter struct {
-	url  string
-	data any
-}
-
 func (s *Whatsmiau) getInstance(id string) *models.Instance {
 	ctx, c := context.WithTimeout(context.Background(), time.Second*5)
 	defer c()
@@ -49,6 +46,11 @@ func (s *Whatsmiau) getInstance(id string) *models.Instance {
 	return &res[0]
 }
 
+// ForgetInstance drops the instance from the cache, so the next events use its new settings
+func (s *Whatsmiau) ForgetInstance(id string) {
+	s.instanceCache.Delete(id)
+}
+
 func (s *Whatsmiau) getInstanceCached(id string) *models.Instance {
 	// Try to get from cache first
 	if cached, found := s.instanceCache.Get(id); found {
@@ -76,30 +78,16 @@ func (s *Whatsmiau) getInstanceCached(id string) *models.Instance {
 	return &res[0]
 }
 
-func (s *Whatsmiau) startEmitter() {
-	for event := range s.emitter {
-		s.sendWebhookWithRetry(event)
-	}
-}
-
-// sendWebhookWithRetry envia webhook com retry automático (3 tentativas: 2s, 5s, 10s)
-func (s *Whatsmiau) sendWebhookWithRetry(event emitter) {
-	maxRetries := 3
-	retryDelays := []time.Duration{
-		2 * time.Second,  // 1ª tentativa após erro
-		5 * time.Second,  // 2ª tentativa após erro
-		10 * time.Second, // 3ª tentativa após erro
-	}
-
-	data, err := json.Marshal(event.data)
-	if err != nil {
-		zap.L().Error("failed to marshal event", zap.Error(err))
-		return
-	}
+// sendWebhookWithRetry envia webhook com retry automático seguindo WEBHOOK_RETRY_SCHEDULE (padrão: 2s, 5s, 10s).
+// Retorna a resposta quando a entrega aceita ações de resposta, para serem executadas depois de registrar a entrega.
+func (s *Whatsmiau) sendWebhookWithRetry(delivery *models.WebhookDelivery) (*webhookResponse, error) {
+	retryDelays := env.Env.WebhookRetrySchedule
+	maxRetries := len(retryDelays)
 
 	var lastErr error
 	var lastStatusCode int
 
+	host := webhookHost(delivery.URL)
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		// Se não é a primeira tentativa, aguarda o delay
 		if attempt > 0 {
@@ -107,13 +95,20 @@ func (s *Whatsmiau) sendWebhookWithRetry(event emitter) {
 			zap.L().Warn("retrying webhook after delay",
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
-				zap.String("url", event.url))
+				zap.String("url", delivery.URL))
 			time.Sleep(delay)
 		}
 
+		// Host fora do ar: não gasta as tentativas, o evento é estacionado na fila de retry
+		if !s.breakers.Allow(host) {
+			return nil, errCircuitOpen
+		}
+		delivery.Attempts++
+
 		// Cria nova requisição para cada tentativa
-		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, event.url, bytes.NewReader(data))
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
 		if err != nil {
+			s.breakers.Record(host, false)
 			lastErr = fmt.Errorf("failed to create request: %w", err)
 			zap.L().Error("webhook request creation failed",
 				zap.Int("attempt", attempt+1),
@@ -121,33 +116,51 @@ func (s *Whatsmiau) sendWebhookWithRetry(event emitter) {
 			continue
 		}
 
+		// custom headers first, they can not replace the content type or the signature
+		for key, value := range delivery.Headers {
+			req.Header.Set(key, value)
+		}
 		req.Header.Set("Content-Type", "application/json")
+		setWebhookHeaders(req, delivery.ID, delivery.Secret, delivery.Payload)
 
 		// Envia requisição
+		start := time.Now()
 		resp, err := s.httpClient.Do(req)
+		delivery.LatencyMs = time.Since(start).Milliseconds()
 		if err != nil {
+			s.breakers.Record(host, false)
+			delivery.StatusCode = 0
+			delivery.Response = ""
 			lastErr = fmt.Errorf("failed to send request: %w", err)
 			zap.L().Error("webhook send failed",
 				zap.Int("attempt", attempt+1),
 				zap.Error(err),
-				zap.String("url", event.url))
+				zap.String("url", delivery.URL))
 			continue
 		}
 
 		lastStatusCode = resp.StatusCode
 
-		// Lê resposta
-		respBody, readErr := io.ReadAll(resp.Body)
+		// Lê resposta (limitada, o corpo pode trazer ações de resposta)
+		respBody, readErr := io.ReadAll(io.LimitReader(resp.Body, env.Env.WebhookReplyMaxBytes+1))
+		_, _ = io.Copy(io.Discard, resp.Body)
 		resp.Body.Close()
+		delivery.StatusCode = resp.StatusCode
+		delivery.Response = truncateResponse(respBody)
+		// only server errors mean the host is down, 4xx come from a working receiver
+		s.breakers.Record(host, resp.StatusCode < http.StatusInternalServerError)
 
 		// Sucesso!
-		if resp.StatusCode == http.StatusOK {
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 			if attempt > 0 {
 				zap.L().Info("webhook succeeded after retry",
 					zap.Int("attempt", attempt+1),
-					zap.String("url", event.url))
+					zap.String("url", delivery.URL))
 			}
-			return
+			if delivery.Reply != nil && readErr == nil {
+				return &webhookResponse{contentType: resp.Header.Get("Content-Type"), body: respBody}, nil
+			}
+			return nil, nil
 		}
 
 		// Falhou mas temos a resposta
@@ -161,7 +174,7 @@ func (s *Whatsmiau) sendWebhookWithRetry(event emitter) {
 			zap.Int("attempt", attempt+1),
 			zap.Int("statusCode", resp.StatusCode),
 			zap.String("response", string(respBody)),
-			zap.String("url", event.url))
+			zap.String("url", delivery.URL))
 	}
 
 	// Todas as tentativas falharam
@@ -169,11 +182,132 @@ func (s *Whatsmiau) sendWebhookWithRetry(event emitter) {
 		zap.Int("totalAttempts", maxRetries+1),
 		zap.Int("lastStatusCode", lastStatusCode),
 		zap.Error(lastErr),
-		zap.String("url", event.url))
+		zap.String("url", delivery.URL))
+
+	return nil, lastErr
 }
 
-func (s *Whatsmiau) emit(body any, url string) {
-	s.emitter <- emitter{url, body}
+// webhookCredentials fills the headers and secret of the delivery from its webhook. They are not stored
+// on the outbox, so pending deliveries also pick up a rotated secret.
+func (s *Whatsmiau) webhookCredentials(delivery *models.WebhookDelivery) {
+	instance := s.getInstanceCached(delivery.InstanceID)
+	if instance == nil {
+		return
+	}
+
+	webhook := instance.FindWebhook(delivery.WebhookID)
+	if webhook == nil {
+		return
+	}
+
+	delivery.Headers = webhook.Headers
+	delivery.Secret = webhook.Secret
+	if delivery.Secret == "" {
+		delivery.Secret = instance.Webhook.Secret
+	}
+}
+
+// truncateResponse keeps the beginning of the webhook response for the delivery log
+func truncateResponse(body []byte) string {
+	const maxResponseLog = 1024
+	if len(body) > maxResponseLog {
+		return string(body[:maxResponseLog]) + "..."
+	}
+
+	return string(body)
+}
+
+// emit enfileira o evento para os webhooks (ou broker) da instância; a entrega é feita pelo outbox.
+// Retorna os IDs das entregas criadas.
+func (s *Whatsmiau) emit(body any, instance *models.Instance) []string {
+	s.publish(instance.ID, body)
+
+	sink := instanceSink(instance)
+	webhooks := s.subscriptions(instance)
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	evt, isWook := body.(wookEvent)
+	if !isWook && sink != SinkWebhook {
+		return nil
+	}
+
+	var ids []string
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		if isWook && !subscribed(webhook, evt.EventName().SubscriptionName()) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:         uuid.NewString(),
+			InstanceID: instance.ID,
+			WebhookID:  webhook.ID,
+			Partition:  instance.ID,
+			CreatedAt:  time.Now(),
+		}
+
+		if sink == SinkWebhook {
+			delivery.URL = webhook.Url
+			delivery.Reply = replyTo(body, instance)
+			delivery.Batch = webhook.Batch
+		} else {
+			delivery.Sink = sink
+		}
+
+		destination := delivery.URL
+		if isWook {
+			delivery.Event = string(evt.EventName())
+			if sink == SinkWebhook && webhook.ByEvents != nil && *webhook.ByEvents {
+				delivery.URL = webhookURLByEvent(webhook.Url, evt.EventName())
+				destination = delivery.URL
+			} else if sink != SinkWebhook {
+				destination = SinkSubject(instance.ID, delivery.Event)
+			}
+			if chat := evt.ChatJID(); chat != "" && env.Env.WebhookPartitionBy == "chat" {
+				delivery.Partition += "/" + chat
+			}
+		}
+
+		// each destination keeps its own order, a slow one does not hold the others
+		if webhook.ID != "" {
+			delivery.Partition += "#" + webhook.ID
+		}
+
+		toRender := body
+		if webhook.Base64 == nil || !*webhook.Base64 {
+			toRender = withoutBase64(body)
+		}
+
+		payload, err := json.Marshal(renderPayload(toRender, webhookProfile(webhook), instance, destination))
+		if err != nil {
+			zap.L().Error("failed to marshal event", zap.Error(err))
+			return ids
+		}
+		delivery.Payload = payload
+
+		s.enqueue(delivery)
+		ids = append(ids, delivery.ID)
+	}
+
+	return ids
+}
+
+// webhookURLByEvent appends the event name to the webhook path, like Evolution does
+// when byEvents is enabled (e.g. https://host/webhook/messages-upsert)
+func webhookURLByEvent(rawURL string, event Wook) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return strings.TrimSuffix(rawURL, "/") + "/" + event.Kebab()
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + event.Kebab()
+	if u.RawPath != "" {
+		u.RawPath = strings.TrimSuffix(u.RawPath, "/") + "/" + event.Kebab()
+	}
+
+	return u.String()
 }
 
 // emitConnectionUpdate é uma função helper para enviar webhooks de status de conexão
@@ -185,11 +319,7 @@ func (s *Whatsmiau) emitConnectionUpdate(id string, status string) {
 	}
 
 	// Verifica se o usuário se inscreveu para este evento específico
-	eventMap := make(map[string]bool)
-	for _, event := range instance.Webhook.Events {
-		eventMap[event] = true
-	}
-
+	eventMap := s.instanceEvents(instance)
 	if !eventMap["CONNECTION_UPDATE"] {
 		// O usuário não quer receber este evento, então não fazemos nada.
 		return
@@ -206,7 +336,141 @@ func (s *Whatsmiau) emitConnectionUpdate(id string, status string) {
 	}
 
 	zap.L().Info("emitting connection update", zap.String("instance", id), zap.String("status", status))
-	s.emit(payload, instance.Webhook.Url)
+	s.emit(payload, instance)
+}
+
+func (s *Whatsmiau) recordMessageStatus(id, messageID string, status WookMessageUpdateStatus, participant string, timestamp time.Time) {
+	ctx, c := context.WithTimeout(context.Background(), 5*time.Second)
+	defer c()
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	if err := s.statuses.Add(ctx, id, messageID, &models.MessageStatusEntry{
+		Status:      string(status),
+		Participant: participant,
+		Timestamp:   timestamp,
+	}); err != nil {
+		zap.L().Error("failed to record message status", zap.String("instance", id), zap.String("message", messageID), zap.Error(err))
+	}
+}
+
+// handleSentMessage records the SERVER_ACK of a message sent by the API and reports it to the webhooks
+func (s *Whatsmiau) handleSentMessage(id string, chat types.JID, res whatsmeow.SendResponse, message *waE2E.Message) {
+	s.recordMessageStatus(id, res.ID, MessageStatusServerAck, "", res.Timestamp)
+
+	instance := s.getInstanceCached(id)
+	if instance == nil {
+		zap.L().Warn("no instance found for send event", zap.String("instance", id))
+		return
+	}
+
+	s.emitSentStatus(instance, chat, res.ID, MessageStatusServerAck, res.Timestamp)
+	s.emitSentMessage(instance, chat, res, message)
+}
+
+// handleSendError records the ERROR of a message the API failed to send and reports it to the webhooks
+func (s *Whatsmiau) handleSendError(id string, chat types.JID, res whatsmeow.SendResponse) {
+	// failed before whatsmeow assigned an id, e.g. not logged in
+	if res.ID == "" {
+		return
+	}
+
+	now := time.Now()
+	s.recordMessageStatus(id, res.ID, MessageStatusError, "", now)
+
+	instance := s.getInstanceCached(id)
+	if instance == nil {
+		zap.L().Warn("no instance found for send event", zap.String("instance", id))
+		return
+	}
+
+	s.emitSentStatus(instance, chat, res.ID, MessageStatusError, now)
+}
+
+// emitSentStatus reports the status of a message sent by the API as messages.update
+func (s *Whatsmiau) emitSentStatus(instance *models.Instance, chat types.JID, messageID string, status WookMessageUpdateStatus, timestamp time.Time) {
+	if !s.instanceEvents(instance)["MESSAGES_UPDATE"] {
+		return
+	}
+
+	chatJid, chatLid := s.GetJidLid(context.Background(), instance.ID, chat)
+	s.emit(&WookEvent[WookMessageUpdateData]{
+		Instance: instance.ID,
+		Data: &WookMessageUpdateData{
+			MessageId:  messageID,
+			KeyId:      messageID,
+			RemoteJid:  chatJid,
+			RemoteLid:  chatLid,
+			FromMe:     true,
+			Status:     status,
+			InstanceId: instance.ID,
+		},
+		DateTime: timestamp,
+		Event:    WookMessagesUpdate,
+	}, instance)
+}
+
+// emitSentMessage reports a message sent by the API as a send.message event, with the same data of messages.upsert
+func (s *Whatsmiau) emitSentMessage(instance *models.Instance, chat types.JID, res whatsmeow.SendResponse, message *waE2E.Message) {
+	id := instance.ID
+	if !s.instanceEvents(instance)["SEND_MESSAGE"] {
+		return
+	}
+
+	client, ok := s.clients.Load(id)
+	if !ok || client.Store == nil || client.Store.ID == nil {
+		return
+	}
+
+	sender := res.Sender
+	if sender.IsEmpty() {
+		sender = client.Store.ID.ToNonAD()
+	}
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     chat,
+				Sender:   sender,
+				IsFromMe: true,
+				IsGroup:  chat.Server == types.GroupServer,
+			},
+			ID:        res.ID,
+			PushName:  client.Store.PushName,
+			Timestamp: res.Timestamp,
+		},
+		Message: message,
+	}
+
+	messageData := s.convertEventMessage(id, instance, evt)
+	if messageData == nil {
+		zap.L().Error("failed to convert sent message", zap.String("instance", id), zap.String("message", res.ID))
+		return
+	}
+
+	messageData.InstanceId = instance.ID
+
+	s.emit(&WookEvent[WookMessageData]{
+		Instance: instance.ID,
+		Data:     messageData,
+		DateTime: time.Unix(int64(messageData.MessageTimestamp), 0),
+		Event:    WookSendMessage,
+	}, instance)
+}
+
+// PingWebhooks sends a webhook.test event to every webhook of the instance through the regular delivery path,
+// returning the delivery IDs to be checked on the delivery log
+func (s *Whatsmiau) PingWebhooks(instance *models.Instance) []string {
+	return s.emit(&WookEvent[WookWebhookTestData]{
+		Instance: instance.ID,
+		Data: &WookWebhookTestData{
+			Message: "ping",
+		},
+		DateTime: time.Now(),
+		Event:    WookWebhookTest,
+	}, instance)
 }
 
 func (s *Whatsmiau) Handle(id string) whatsmeow.EventHandler {
@@ -220,10 +484,7 @@ func (s *Whatsmiau) Handle(id string) whatsmeow.EventHandler {
 				return
 			}
 
-			eventMap := make(map[string]bool)
-			for _, event := range instance.Webhook.Events {
-				eventMap[event] = true
-			}
+			eventMap := s.instanceEvents(instance)
 
 			switch e := evt.(type) {
 			case *events.LoggedOut:
@@ -242,6 +503,8 @@ func (s *Whatsmiau) Handle(id string) whatsmeow.EventHandler {
 				s.handleHistorySyncEvent(id, instance, e, eventMap)
 			case *events.GroupInfo:
 				s.handleGroupInfoEvent(id, instance, e, eventMap)
+			case *events.JoinedGroup:
+				s.handleJoinedGroupEvent(id, instance, e, eventMap)
 			case *events.PushName:
 				s.handlePushNameEvent(id, instance, e, eventMap)
 			default:
@@ -266,6 +529,16 @@ func (s *Whatsmiau) handleLoggedOut(id string) {
 	s.clients.Delete(id)
 }
 func (s *Whatsmiau) handleMessageEvent(id string, instance *models.Instance, e *events.Message, eventMap map[string]bool) {
+	if e.Info.Chat.Server == types.NewsletterServer {
+		s.handleNewsletterMessage(instance, e, eventMap)
+		return
+	}
+
+	if e.Info.Chat == types.StatusBroadcastJID {
+		s.handleStatusMessage(id, instance, e, eventMap)
+		return
+	}
+
 	if !eventMap["MESSAGES_UPSERT"] {
 		return
 	}
@@ -313,23 +586,77 @@ func (s *Whatsmiau) handleMessageEvent(id string, instance *models.Instance, e *
 		zap.L().Debug("message event", zap.String("instance", id), zap.Any("data", wookMessage.Data))
 	}
 
-	s.emit(wookMessage, instance.Webhook.Url)
+	s.emit(wookMessage, instance)
 }
 
-func (s *Whatsmiau) handleReceiptEvent(id string, instance *models.Instance, e *events.Receipt, eventMap map[string]bool) {
-	if !eventMap["MESSAGES_UPDATE"] {
+// handleNewsletterMessage emits the posts of followed channels, which have no sender nor receipts
+func (s *Whatsmiau) handleNewsletterMessage(instance *models.Instance, e *events.Message, eventMap map[string]bool) {
+	if !eventMap["NEWSLETTER_MESSAGE"] || e.Message == nil {
 		return
 	}
 
-	if canIgnoreGroup(e, instance) {
+	messageType, raw, _ := s.parseWAMessage(e.Message)
+	s.emit(&WookEvent[WookNewsletterMessageData]{
+		Instance: instance.ID,
+		Data: &WookNewsletterMessageData{
+			NewsletterJid:    e.Info.Chat.String(),
+			ServerId:         int(e.Info.ServerID),
+			MessageId:        e.Info.ID,
+			MessageType:      messageType,
+			Message:          raw,
+			MessageTimestamp: int(e.Info.Timestamp.Unix()),
+			FromMe:           e.Info.IsFromMe,
+			InstanceId:       instance.ID,
+		},
+		DateTime: e.Info.Timestamp,
+		Event:    WookNewsletterMessage,
+	}, instance)
+}
+
+// handleStatusMessage views the statuses of contacts when ReadStatus is enabled and emits them as status.upsert
+// when ReceiveStatus is enabled
+func (s *Whatsmiau) handleStatusMessage(id string, instance *models.Instance, e *events.Message, eventMap map[string]bool) {
+	if instance.ReadStatus && !e.Info.IsFromMe && e.Message.GetProtocolMessage() == nil {
+		go s.markAsReadAfterDelay(id, e, 0)
+	}
+
+	if instance.ReceiveStatus == nil || !*instance.ReceiveStatus || !eventMap["STATUS_UPSERT"] {
+		return
+	}
+
+	messageData := s.convertEventMessage(id, instance, e)
+	if messageData == nil {
 		return
 	}
+	messageData.InstanceId = instance.ID
+
+	s.emit(&WookEvent[WookMessageData]{
+		Instance: instance.ID,
+		Data:     messageData,
+		DateTime: e.Info.Timestamp,
+		Event:    WookStatusUpsert,
+	}, instance)
+}
 
+func (s *Whatsmiau) handleReceiptEvent(id string, instance *models.Instance, e *events.Receipt, eventMap map[string]bool) {
 	data := s.convertEventReceipt(id, e)
 	if data == nil {
 		return
 	}
 
+	// the timeline is kept even when the event is not subscribed
+	for _, event := range data {
+		s.recordMessageStatus(id, event.MessageId, event.Status, event.Participant, e.Timestamp)
+	}
+
+	if !eventMap["MESSAGES_UPDATE"] {
+		return
+	}
+
+	if canIgnoreGroup(e, instance) {
+		return
+	}
+
 	for _, event := range data {
 		wookData := &WookEvent[WookMessageUpdateData]{
 			Instance: instance.ID,
@@ -338,7 +665,7 @@ func (s *Whatsmiau) handleReceiptEvent(id string, instance *models.Instance, e *
 			Event:    WookMessagesUpdate,
 		}
 
-		s.emit(wookData, instance.Webhook.Url)
+		s.emit(wookData, instance)
 	}
 }
 
@@ -360,7 +687,7 @@ func (s *Whatsmiau) handleBusinessNameEvent(id string, instance *models.Instance
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
 }
 
 func (s *Whatsmiau) handleContactEvent(id string, instance *models.Instance, e *events.Contact, eventMap map[string]bool) {
@@ -385,10 +712,13 @@ func (s *Whatsmiau) handleContactEvent(id string, instance *models.Instance, e *
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
 }
 
 func (s *Whatsmiau) handlePictureEvent(id string, instance *models.Instance, e *events.Picture, eventMap map[string]bool) {
+	// the cached picture is stale even when the event is not sent
+	s.forgetPicture(id, e.JID)
+
 	if !eventMap["CONTACTS_UPSERT"] {
 		return
 	}
@@ -405,7 +735,7 @@ func (s *Whatsmiau) handlePictureEvent(id string, instance *models.Instance, e *
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
 }
 
 func (s *Whatsmiau) handleHistorySyncEvent(id string, instance *models.Instance, e *events.HistorySync, eventMap map[string]bool) {
@@ -425,15 +755,23 @@ func (s *Whatsmiau) handleHistorySyncEvent(id string, instance *models.Instance,
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
 }
 
 func (s *Whatsmiau) handleGroupInfoEvent(id string, instance *models.Instance, e *events.GroupInfo, eventMap map[string]bool) {
-	if !eventMap["CONTACTS_UPSERT"] {
+	if instance.GroupsIgnore {
 		return
 	}
 
-	if instance.GroupsIgnore {
+	if eventMap["GROUP_UPDATE"] {
+		s.emitGroupUpdate(id, instance, e)
+	}
+
+	if eventMap["GROUP_PARTICIPANTS_UPDATE"] {
+		s.emitGroupParticipantsUpdate(id, instance, e)
+	}
+
+	if !eventMap["CONTACTS_UPSERT"] {
 		return
 	}
 
@@ -450,7 +788,155 @@ func (s *Whatsmiau) handleGroupInfoEvent(id string, instance *models.Instance, e
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
+}
+
+func (s *Whatsmiau) handleJoinedGroupEvent(id string, instance *models.Instance, e *events.JoinedGroup, eventMap map[string]bool) {
+	if !eventMap["GROUPS_UPSERT"] {
+		return
+	}
+
+	if instance.GroupsIgnore {
+		return
+	}
+
+	group := s.convertGroupMetadata(context.Background(), id, &e.GroupInfo, true)
+	s.emit(&WookEvent[WookGroupsUpsertData]{
+		Instance: instance.ID,
+		Data:     &WookGroupsUpsertData{*group},
+		DateTime: time.Now(),
+		Event:    WookGroupsUpsert,
+	}, instance)
+}
+
+// groupAuthor returns who made the group change, preferring the phone number
+func (s *Whatsmiau) groupAuthor(id string, e *events.GroupInfo) string {
+	if e.SenderPN != nil && !e.SenderPN.IsEmpty() {
+		return e.SenderPN.String()
+	}
+
+	if e.Sender == nil || e.Sender.IsEmpty() {
+		return ""
+	}
+
+	jid, _ := s.GetJidLid(context.Background(), id, *e.Sender)
+	return jid
+}
+
+func (s *Whatsmiau) emitGroupUpdate(id string, instance *models.Instance, e *events.GroupInfo) {
+	update := WookGroupUpdate{
+		Id: e.JID.String(),
+	}
+
+	changed := false
+	if e.Name != nil {
+		update.Subject = &e.Name.Name
+		changed = true
+	}
+	if e.Topic != nil {
+		update.Desc = &e.Topic.Topic
+		changed = true
+	}
+	if e.Locked != nil {
+		update.Restrict = &e.Locked.IsLocked
+		changed = true
+	}
+	if e.Announce != nil {
+		update.Announce = &e.Announce.IsAnnounce
+		changed = true
+	}
+	if e.Ephemeral != nil {
+		update.EphemeralDuration = &e.Ephemeral.DisappearingTimer
+		changed = true
+	}
+	if e.MembershipApprovalMode != nil {
+		update.JoinApprovalMode = &e.MembershipApprovalMode.IsJoinApprovalRequired
+		changed = true
+	}
+	if e.NewInviteLink != nil {
+		update.InviteLink = *e.NewInviteLink
+		changed = true
+	}
+	if e.Delete != nil {
+		update.Deleted = e.Delete.Deleted
+		changed = true
+	}
+	if e.Link != nil {
+		update.Linked = convertGroupLink(e.Link)
+		changed = true
+	}
+	if e.Unlink != nil {
+		update.Unlinked = convertGroupLink(e.Unlink)
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+
+	update.Author = s.groupAuthor(id, e)
+	s.emit(&WookEvent[WookGroupUpdateData]{
+		Instance: instance.ID,
+		Data:     &WookGroupUpdateData{update},
+		DateTime: e.Timestamp,
+		Event:    WookGroupsUpdate,
+	}, instance)
+}
+
+func convertGroupLink(change *types.GroupLinkChange) *WookGroupLink {
+	return &WookGroupLink{
+		Type:                string(change.Type),
+		Id:                  change.Group.JID.String(),
+		Subject:             change.Group.Name,
+		IsCommunityAnnounce: change.Group.IsDefaultSubGroup,
+		Reason:              string(change.UnlinkReason),
+	}
+}
+
+func (s *Whatsmiau) emitGroupParticipantsUpdate(id string, instance *models.Instance, e *events.GroupInfo) {
+	changes := []struct {
+		action string
+		jids   []types.JID
+	}{
+		{"add", e.Join},
+		{"remove", e.Leave},
+		{"promote", e.Promote},
+		{"demote", e.Demote},
+	}
+
+	var author string
+	for _, change := range changes {
+		if len(change.jids) == 0 {
+			continue
+		}
+
+		if author == "" {
+			author = s.groupAuthor(id, e)
+		}
+
+		participants := make([]string, 0, len(change.jids))
+		for _, jid := range change.jids {
+			participant, _ := s.GetJidLid(context.Background(), id, jid)
+			participants = append(participants, participant)
+		}
+
+		data := &WookGroupParticipantsUpdateData{
+			Id:           e.JID.String(),
+			Author:       author,
+			Participants: participants,
+			Action:       change.action,
+		}
+		if change.action == "add" {
+			data.Reason = e.JoinReason
+		}
+
+		s.emit(&WookEvent[WookGroupParticipantsUpdateData]{
+			Instance: instance.ID,
+			Data:     data,
+			DateTime: e.Timestamp,
+			Event:    WookGroupParticipantsUpdate,
+		}, instance)
+	}
 }
 
 func (s *Whatsmiau) handlePushNameEvent(id string, instance *models.Instance, e *events.PushName, eventMap map[string]bool) {
@@ -475,7 +961,7 @@ func (s *Whatsmiau) handlePushNameEvent(id string, instance *models.Instance, e
 		Event:    WookContactsUpsert,
 	}
 
-	s.emit(wookData, instance.Webhook.Url)
+	s.emit(wookData, instance)
 }
 
 // parseWAMessage converts a raw waE2E.Message into our internal representation.
@@ -616,6 +1102,17 @@ func (s *Whatsmiau) parseWAMessage(m *waE2E.Message) (string, *WookMessageRaw, *
 			Contacts:    contacts,
 		}
 		ci = contactArray.GetContextInfo()
+	} else if invite := m.GetGroupInviteMessage(); invite != nil {
+		messageType = "groupInviteMessage"
+		raw.GroupInviteMessage = &GroupInviteMessageRaw{
+			GroupJid:         invite.GetGroupJID(),
+			InviteCode:       invite.GetInviteCode(),
+			InviteExpiration: i64(invite.GetInviteExpiration()),
+			GroupName:        invite.GetGroupName(),
+			JpegThumbnail:    b64(invite.GetJPEGThumbnail()),
+			Caption:          invite.GetCaption(),
+		}
+		ci = invite.GetContextInfo()
 	} else if conv := strings.TrimSpace(m.GetConversation()); conv != "" {
 		messageType = "conversation"
 		raw.Conversation = conv
@@ -833,19 +1330,36 @@ func (s *Whatsmiau) convertEventMessage(id string, instance *models.Instance, ev
 	}
 }
 
-func (s *Whatsmiau) convertEventReceipt(id string, evt *events.Receipt) []WookMessageUpdateData {
-	var status WookMessageUpdateStatus
-	switch evt.Type {
-	case types.ReceiptTypeRead:
-		status = MessageStatusRead
+// receiptStatus maps a receipt to the message status it means, false for receipts that are not about
+// the message status (retry is handled by whatsmeow, sender receipts come from our own devices)
+func receiptStatus(receiptType types.ReceiptType) (WookMessageUpdateStatus, bool) {
+	switch receiptType {
+	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
+		return MessageStatusRead, true
 	case types.ReceiptTypeDelivered:
-		status = MessageStatusDeliveryAck
+		return MessageStatusDeliveryAck, true
+	case types.ReceiptTypePlayed, types.ReceiptTypePlayedSelf:
+		return MessageStatusPlayed, true
+	case types.ReceiptTypeServerError:
+		return MessageStatusError, true
 	default:
+		return "", false
+	}
+}
+
+func (s *Whatsmiau) convertEventReceipt(id string, evt *events.Receipt) []WookMessageUpdateData {
+	status, ok := receiptStatus(evt.Type)
+	if !ok {
 		return nil
 	}
 
 	chatJid, chatLid := s.GetJidLid(context.Background(), id, evt.Chat)
-	participantJid, _ := s.GetJidLid(context.Background(), id, evt.Sender)
+
+	// only group receipts say which participant got the message
+	var participantJid string
+	if evt.IsGroup {
+		participantJid, _ = s.GetJidLid(context.Background(), id, evt.Sender)
+	}
 
 	var result []WookMessageUpdateData
 	for _, messageID := range evt.MessageIDs {
@@ -871,6 +1385,21 @@ func (s *Whatsmiau) uploadMessageFile(ctx context.Context, instance *models.Inst
 		ext       string
 	)
 
+	wantsBase64 := s.instanceWantsBase64(instance)
+	fileSHA256 := fileMessage.GetFileSHA256()
+
+	// Fast path: the same media was already stored, there is no need to download it again
+	if s.fileStorage != nil && !wantsBase64 && len(fileSHA256) > 0 {
+		if ext = extractExtFromName(fileName, mimetype); ext != "" {
+			url, exists, err := s.fileStorage.Exists(ctx, mediaObjectName(fileSHA256, ext))
+			if err != nil {
+				zap.L().Warn("failed to check if media exists", zap.Error(err))
+			} else if exists {
+				return url, ""
+			}
+		}
+	}
+
 	tmpFile, err := os.CreateTemp("", "file-*")
 	if err != nil {
 		panic(err)
@@ -887,7 +1416,7 @@ func (s *Whatsmiau) uploadMessageFile(ctx context.Context, instance *models.Inst
 	}
 
 	ext = extractExtFromFile(fileName, mimetype, tmpFile)
-	if instance.Webhook.Base64 != nil && *instance.Webhook.Base64 {
+	if wantsBase64 {
 		data, err := io.ReadAll(tmpFile)
 		if err != nil {
 			zap.L().Error("failed to read image", zap.Error(err))
@@ -896,11 +1425,21 @@ func (s *Whatsmiau) uploadMessageFile(ctx context.Context, instance *models.Inst
 		}
 	}
 	if s.fileStorage != nil {
+		objectName := mediaObjectName(fileSHA256, ext)
+		if len(fileSHA256) > 0 {
+			url, exists, err := s.fileStorage.Exists(ctx, objectName)
+			if err != nil {
+				zap.L().Warn("failed to check if media exists", zap.Error(err))
+			} else if exists {
+				return url, b64Result
+			}
+		}
+
 		if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
 			zap.L().Error("failed to seek image", zap.Error(err))
 		}
 
-		urlResult, _, err = s.fileStorage.Upload(ctx, uuid.NewString()+"."+ext, mimetype, tmpFile)
+		urlResult, _, err = s.fileStorage.Upload(ctx, objectName, mimetype, tmpFile)
 		if err != nil {
 			zap.L().Error("failed to upload image", zap.Error(err))
 		}
@@ -1084,37 +1623,19 @@ func (s *Whatsmiau) markAsReadAfterDelay(id string, e *events.Message, delay tim
 }
 
 func (s *Whatsmiau) getPic(id string, jid types.JID) (string, string, error) {
-	client, ok := s.clients.Load(id)
-	if !ok || client == nil {
-		zap.L().Warn("no client for event", zap.String("id", id))
-		return "", "", fmt.Errorf("no client for event %s", id)
-	}
-
-	pic, err := client.GetProfilePictureInfo(context.TODO(), jid, &whatsmeow.GetProfilePictureParams{
-		Preview:     true,
-		IsCommunity: false,
-	})
+	ctx := context.TODO()
+	picture, err := s.profilePicture(ctx, id, jid, true)
 	if err != nil {
 		return "", "", nil
 	}
 
-	if pic == nil {
-		return "", "", err
-	}
-
-	res, err := s.httpClient.Get(pic.URL)
-	if err != nil {
-		zap.L().Error("get profile picture error", zap.String("id", id), zap.Error(err))
-		return "", "", err
-	}
-
-	picRaw, err := io.ReadAll(res.Body)
+	b64, err := s.pictureData(ctx, jid, picture, true)
 	if err != nil {
 		zap.L().Error("get profile picture error", zap.String("id", id), zap.Error(err))
 		return "", "", err
 	}
 
-	return pic.URL, base64.StdEncoding.EncodeToString(picRaw), nil
+	return picture.URL, b64, nil
 }
 
 // keepAlwaysOnlineManager gerencia presence de todas as instâncias de forma centralizada
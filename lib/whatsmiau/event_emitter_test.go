@@ -0,0 +1,245 @@
+package whatsmiau
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestReceiptStatus(t *testing.T) {
+	tests := []struct {
+		receipt types.ReceiptType
+		want    WookMessageUpdateStatus
+		wantOk  bool
+	}{
+		{receipt: types.ReceiptTypeDelivered, want: MessageStatusDeliveryAck, wantOk: true},
+		{receipt: types.ReceiptTypeRead, want: MessageStatusRead, wantOk: true},
+		{receipt: types.ReceiptTypeReadSelf, want: MessageStatusRead, wantOk: true},
+		{receipt: types.ReceiptTypePlayed, want: MessageStatusPlayed, wantOk: true},
+		{receipt: types.ReceiptTypePlayedSelf, want: MessageStatusPlayed, wantOk: true},
+		{receipt: types.ReceiptTypeServerError, want: MessageStatusError, wantOk: true},
+		{receipt: types.ReceiptTypeSender, wantOk: false},
+		{receipt: types.ReceiptTypeRetry, wantOk: false},
+		{receipt: types.ReceiptTypeInactive, wantOk: false},
+		{receipt: types.ReceiptTypePeerMsg, wantOk: false},
+		{receipt: types.ReceiptTypeHistorySync, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run("receipt "+string(tt.receipt), func(t *testing.T) {
+			got, ok := receiptStatus(tt.receipt)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("receiptStatus(%q) = %q, %t, want %q, %t", tt.receipt, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConvertEventReceipt(t *testing.T) {
+	s := &Whatsmiau{clients: xsync.NewMap[string, *whatsmeow.Client]()}
+
+	contact := types.NewJID("5511988887777", types.DefaultUserServer)
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	participant := types.NewJID("5511977776666", types.DefaultUserServer)
+	participant.Device = 3
+
+	tests := []struct {
+		name            string
+		receipt         *events.Receipt
+		wantStatus      WookMessageUpdateStatus
+		wantChat        string
+		wantParticipant string
+		wantNil         bool
+	}{
+		{
+			name: "direct chat has no participant",
+			receipt: &events.Receipt{
+				MessageSource: types.MessageSource{Chat: contact, Sender: contact, IsFromMe: true},
+				MessageIDs:    []string{"A", "B"},
+				Type:          types.ReceiptTypeDelivered,
+			},
+			wantStatus: MessageStatusDeliveryAck,
+			wantChat:   "5511988887777@s.whatsapp.net",
+		},
+		{
+			name: "group chat reports the participant without the device",
+			receipt: &events.Receipt{
+				MessageSource: types.MessageSource{Chat: group, Sender: participant, IsFromMe: true, IsGroup: true},
+				MessageIDs:    []string{"A"},
+				Type:          types.ReceiptTypeRead,
+			},
+			wantStatus:      MessageStatusRead,
+			wantChat:        "120363025246125486@g.us",
+			wantParticipant: "5511977776666@s.whatsapp.net",
+		},
+		{
+			name: "sender receipt is ignored",
+			receipt: &events.Receipt{
+				MessageSource: types.MessageSource{Chat: contact, Sender: contact, IsFromMe: true},
+				MessageIDs:    []string{"A"},
+				Type:          types.ReceiptTypeSender,
+			},
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.receipt.Timestamp = time.Unix(1735830245, 0)
+
+			got := s.convertEventReceipt("my-instance", tt.receipt)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("convertEventReceipt() = %+v, want nil", got)
+				}
+				return
+			}
+
+			if len(got) != len(tt.receipt.MessageIDs) {
+				t.Fatalf("convertEventReceipt() returned %d updates, want %d", len(got), len(tt.receipt.MessageIDs))
+			}
+			for i, update := range got {
+				if update.MessageId != tt.receipt.MessageIDs[i] || update.KeyId != tt.receipt.MessageIDs[i] {
+					t.Errorf("update %d id = %q/%q, want %q", i, update.MessageId, update.KeyId, tt.receipt.MessageIDs[i])
+				}
+				if update.Status != tt.wantStatus {
+					t.Errorf("update %d status = %q, want %q", i, update.Status, tt.wantStatus)
+				}
+				if update.RemoteJid != tt.wantChat {
+					t.Errorf("update %d remoteJid = %q, want %q", i, update.RemoteJid, tt.wantChat)
+				}
+				if update.Participant != tt.wantParticipant {
+					t.Errorf("update %d participant = %q, want %q", i, update.Participant, tt.wantParticipant)
+				}
+				if !update.FromMe || update.InstanceId != "my-instance" {
+					t.Errorf("update %d fromMe/instanceId = %t/%q, want true/my-instance", i, update.FromMe, update.InstanceId)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookURLByEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "path", url: "https://receiver.example.com/webhook", want: "https://receiver.example.com/webhook/messages-upsert"},
+		{name: "trailing slash", url: "https://receiver.example.com/webhook/", want: "https://receiver.example.com/webhook/messages-upsert"},
+		{name: "host only", url: "https://receiver.example.com", want: "https://receiver.example.com/messages-upsert"},
+		{name: "query string", url: "https://receiver.example.com/webhook?token=abc&x=1", want: "https://receiver.example.com/webhook/messages-upsert?token=abc&x=1"},
+		{name: "trailing slash and query string", url: "https://receiver.example.com/webhook/?token=abc", want: "https://receiver.example.com/webhook/messages-upsert?token=abc"},
+		{name: "escaped path", url: "https://receiver.example.com/hooks/a%2Fb", want: "https://receiver.example.com/hooks/a%2Fb/messages-upsert"},
+		{name: "escaped path with trailing slash", url: "https://receiver.example.com/hooks/a%2Fb/", want: "https://receiver.example.com/hooks/a%2Fb/messages-upsert"},
+		{name: "unparseable url", url: "http://receiver.example.com/%zz/", want: "http://receiver.example.com/%zz/messages-upsert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhookURLByEvent(tt.url, WookMessagesUpsert); got != tt.want {
+				t.Errorf("webhookURLByEvent(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendWebhookHeaders(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookRetrySchedule = nil
+	env.Env.WebhookBreakerThreshold = 0
+	env.Env.WebhookReplyMaxBytes = 1024
+
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	s := &Whatsmiau{httpClient: server.Client(), breakers: newCircuitBreakers()}
+	payload := []byte(`{"event":"messages.upsert"}`)
+
+	tests := []struct {
+		name    string
+		secret  string
+		headers map[string]string
+	}{
+		{
+			name:    "custom headers are sent",
+			secret:  "my-secret",
+			headers: map[string]string{"Authorization": "Bearer token", "X-Tenant": "acme"},
+		},
+		{
+			name:   "custom headers do not replace the content type or the signature",
+			secret: "my-secret",
+			headers: map[string]string{
+				"Authorization":        "Bearer token",
+				"Content-Type":         "text/plain",
+				HeaderWebhookSignature: "sha256=forged",
+				HeaderWebhookTimestamp: "1",
+				HeaderWebhookDelivery:  "forged",
+			},
+		},
+		{
+			name:    "a custom signature is dropped without secret",
+			headers: map[string]string{"Authorization": "Bearer token", HeaderWebhookSignature: "sha256=forged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := &models.WebhookDelivery{
+				ID:      "delivery-1",
+				URL:     server.URL,
+				Secret:  tt.secret,
+				Headers: tt.headers,
+				Payload: payload,
+			}
+			if _, err := s.sendWebhookWithRetry(delivery); err != nil {
+				t.Fatalf("sendWebhookWithRetry() error = %v", err)
+			}
+			header := <-received
+
+			for key, want := range tt.headers {
+				if key == "Content-Type" || key == HeaderWebhookSignature || key == HeaderWebhookTimestamp || key == HeaderWebhookDelivery {
+					continue
+				}
+				if got := header.Get(key); got != want {
+					t.Errorf("header %s = %q, want %q", key, got, want)
+				}
+			}
+
+			if got := header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			if got := header.Get(HeaderWebhookDelivery); got != delivery.ID {
+				t.Errorf("%s = %q, want %q", HeaderWebhookDelivery, got, delivery.ID)
+			}
+
+			timestamp, err := strconv.ParseInt(header.Get(HeaderWebhookTimestamp), 10, 64)
+			if err != nil || timestamp < time.Now().Add(-time.Minute).Unix() {
+				t.Fatalf("%s = %q, want the current time", HeaderWebhookTimestamp, header.Get(HeaderWebhookTimestamp))
+			}
+
+			want := ""
+			if tt.secret != "" {
+				want = SignWebhook(tt.secret, timestamp, payload)
+			}
+			if got := header.Get(HeaderWebhookSignature); got != want {
+				t.Errorf("%s = %q, want %q", HeaderWebhookSignature, got, want)
+			}
+		})
+	}
+}
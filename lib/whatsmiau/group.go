@@ -0,0 +1,353 @@
+package whatsmiau
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+func (s *Whatsmiau) convertGroupMetadata(ctx context.Context, id string, info *types.GroupInfo, withParticipants bool) *models.GroupInfo {
+	result := &models.GroupInfo{
+		ID:       info.JID.String(),
+		Subject:  info.Name,
+		Size:     len(info.Participants),
+		Desc:     info.Topic,
+		DescID:   info.TopicID,
+		Restrict: info.IsLocked,
+		Announce: info.IsAnnounce,
+
+		IsCommunity:         info.IsParent,
+		IsCommunityAnnounce: info.IsDefaultSubGroup,
+	}
+
+	if info.IsParent {
+		s.communities.Store(info.JID.String(), true)
+	}
+	if !info.LinkedParentJID.IsEmpty() {
+		result.LinkedParent = info.LinkedParentJID.String()
+	}
+
+	if !info.NameSetAt.IsZero() {
+		result.SubjectTime = info.NameSetAt.Unix()
+	}
+	if !info.NameSetBy.IsEmpty() {
+		result.SubjectOwner, _ = s.GetJidLid(ctx, id, info.NameSetBy)
+	}
+	if !info.GroupCreated.IsZero() {
+		result.Creation = info.GroupCreated.Unix()
+	}
+	if !info.OwnerJID.IsEmpty() {
+		result.Owner, _ = s.GetJidLid(ctx, id, info.OwnerJID)
+	}
+
+	if !withParticipants {
+		return result
+	}
+
+	result.Participants = make([]models.GroupParticipant, 0, len(info.Participants))
+	for _, participant := range info.Participants {
+		item := models.GroupParticipant{
+			ID: participant.JID.String(),
+		}
+		if !participant.PhoneNumber.IsEmpty() {
+			item.ID = participant.PhoneNumber.String()
+		}
+		if !participant.LID.IsEmpty() {
+			item.Lid = participant.LID.String()
+		}
+
+		switch {
+		case participant.IsSuperAdmin:
+			item.Admin = "superadmin"
+		case participant.IsAdmin:
+			item.Admin = "admin"
+		}
+
+		result.Participants = append(result.Participants, item)
+	}
+
+	return result
+}
+
+type CreateGroupRequest struct {
+	InstanceID   string      `json:"instance_id"`
+	Subject      string      `json:"subject"`
+	Description  string      `json:"description"`
+	Participants []types.JID `json:"participants"`
+}
+
+func (s *Whatsmiau) CreateGroup(ctx context.Context, data *CreateGroupRequest) (*models.GroupInfo, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	info, err := client.CreateGroup(ctx, whatsmeow.ReqCreateGroup{
+		Name:         data.Subject,
+		Participants: data.Participants,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data.Description) > 0 {
+		if err := client.SetGroupDescription(ctx, info.JID, data.Description); err != nil {
+			zap.L().Error("failed to set group description", zap.String("instance", data.InstanceID), zap.String("group", info.JID.String()), zap.Error(err))
+		} else {
+			info.Topic = data.Description
+		}
+	}
+
+	return s.convertGroupMetadata(ctx, data.InstanceID, info, true), nil
+}
+
+func (s *Whatsmiau) FetchAllGroups(ctx context.Context, instanceID string, withParticipants bool) ([]models.GroupInfo, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	groups, err := client.GetJoinedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.GroupInfo, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *s.convertGroupMetadata(ctx, instanceID, group, withParticipants))
+	}
+
+	return result, nil
+}
+
+func (s *Whatsmiau) FindGroupInfo(ctx context.Context, instanceID string, groupJID types.JID) (*models.GroupInfo, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.convertGroupMetadata(ctx, instanceID, info, true)
+
+	// a group without picture returns an error, so it is ignored
+	pic, err := client.GetProfilePictureInfo(ctx, groupJID, &whatsmeow.GetProfilePictureParams{
+		IsCommunity: info.IsParent,
+	})
+	if err == nil && pic != nil {
+		result.PictureURL = pic.URL
+	}
+
+	return result, nil
+}
+
+type UpdateGroupRequest struct {
+	InstanceID  string     `json:"instance_id"`
+	GroupJID    *types.JID `json:"group_jid"`
+	Subject     *string    `json:"subject"`
+	Description *string    `json:"description"`
+	Image       string     `json:"image"` // URL or base64 data URI
+}
+
+// UpdateGroup changes the subject, description and/or picture of the group
+func (s *Whatsmiau) UpdateGroup(ctx context.Context, data *UpdateGroupRequest) error {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	if data.Subject != nil {
+		if err := client.SetGroupName(ctx, *data.GroupJID, *data.Subject); err != nil {
+			return err
+		}
+	}
+
+	if data.Description != nil {
+		if err := client.SetGroupDescription(ctx, *data.GroupJID, *data.Description); err != nil {
+			return err
+		}
+	}
+
+	if len(data.Image) > 0 {
+		raw, err := s.loadMedia(ctx, data.Image)
+		if err != nil {
+			return err
+		}
+
+		// WhatsApp only accepts square JPEG pictures
+		image, err := resizeProfilePicture(raw)
+		if err != nil {
+			return err
+		}
+
+		if _, err := client.SetGroupPhoto(ctx, *data.GroupJID, image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type UpdateGroupParticipantsRequest struct {
+	InstanceID   string      `json:"instance_id"`
+	GroupJID     *types.JID  `json:"group_jid"`
+	Action       string      `json:"action"` // add, remove, promote or demote
+	Participants []types.JID `json:"participants"`
+}
+
+func (s *Whatsmiau) UpdateGroupParticipants(ctx context.Context, data *UpdateGroupParticipantsRequest) ([]models.GroupParticipantUpdate, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	participants, err := client.UpdateGroupParticipants(ctx, *data.GroupJID, data.Participants, whatsmeow.ParticipantChange(data.Action))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.GroupParticipantUpdate, 0, len(participants))
+	for _, participant := range participants {
+		status := "200"
+		if participant.Error != 0 {
+			status = fmt.Sprint(participant.Error)
+		}
+
+		jid := participant.JID
+		if !participant.PhoneNumber.IsEmpty() {
+			jid = participant.PhoneNumber
+		}
+
+		result = append(result, models.GroupParticipantUpdate{
+			Status: status,
+			Jid:    jid.String(),
+		})
+	}
+
+	return result, nil
+}
+
+type UpdateGroupSettingRequest struct {
+	InstanceID string     `json:"instance_id"`
+	GroupJID   *types.JID `json:"group_jid"`
+	Action     string     `json:"action"` // announcement, not_announcement, locked or unlocked
+}
+
+func (s *Whatsmiau) UpdateGroupSetting(ctx context.Context, data *UpdateGroupSettingRequest) error {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	switch data.Action {
+	case "announcement":
+		return client.SetGroupAnnounce(ctx, *data.GroupJID, true)
+	case "not_announcement":
+		return client.SetGroupAnnounce(ctx, *data.GroupJID, false)
+	case "locked":
+		return client.SetGroupLocked(ctx, *data.GroupJID, true)
+	case "unlocked":
+		return client.SetGroupLocked(ctx, *data.GroupJID, false)
+	}
+
+	return fmt.Errorf("invalid group setting: %s", data.Action)
+}
+
+func (s *Whatsmiau) LeaveGroup(ctx context.Context, instanceID string, groupJID types.JID) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	return client.LeaveGroup(ctx, groupJID)
+}
+
+// GroupInviteLink returns the invite link of the group, revoking the current one when reset is true
+func (s *Whatsmiau) GroupInviteLink(ctx context.Context, instanceID string, groupJID types.JID, reset bool) (string, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return "", whatsmeow.ErrClientIsNil
+	}
+
+	return client.GetGroupInviteLink(ctx, groupJID, reset)
+}
+
+// parseInviteCode accepts the invite code or a chat.whatsapp.com link (with or without scheme)
+func parseInviteCode(link string) string {
+	code := strings.TrimSpace(link)
+	code = strings.TrimPrefix(code, "https://")
+	code = strings.TrimPrefix(code, "http://")
+	code = strings.TrimPrefix(code, "chat.whatsapp.com/")
+	code = strings.TrimPrefix(code, "invite/")
+	if i := strings.IndexAny(code, "?#/"); i >= 0 {
+		code = code[:i]
+	}
+
+	return code
+}
+
+// GroupInviteInfo previews the group of an invite link without joining it
+func (s *Whatsmiau) GroupInviteInfo(ctx context.Context, instanceID, link string) (*models.GroupInfo, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	code := parseInviteCode(link)
+	if code == "" {
+		return nil, fmt.Errorf("invalid invite code")
+	}
+
+	info, err := client.GetGroupInfoFromLink(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertGroupMetadata(ctx, instanceID, info, true), nil
+}
+
+// JoinGroupWithLink joins the group of an invite link, returning its JID (or the request JID when the group needs approval)
+func (s *Whatsmiau) JoinGroupWithLink(ctx context.Context, instanceID, link string) (*types.JID, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	code := parseInviteCode(link)
+	if code == "" {
+		return nil, fmt.Errorf("invalid invite code")
+	}
+
+	jid, err := client.JoinGroupWithLink(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jid, nil
+}
+
+type AcceptGroupInviteRequest struct {
+	InstanceID string     `json:"instance_id"`
+	GroupJID   *types.JID `json:"group_jid"`
+	Inviter    *types.JID `json:"inviter"`
+	Code       string     `json:"code"`
+	Expiration int64      `json:"expiration"`
+}
+
+// AcceptGroupInvite joins the group of a groupInviteMessage received in chat
+func (s *Whatsmiau) AcceptGroupInvite(ctx context.Context, data *AcceptGroupInviteRequest) error {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	return client.JoinGroupWithInvite(ctx, *data.GroupJID, *data.Inviter, data.Code, data.Expiration)
+}
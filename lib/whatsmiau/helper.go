@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/verbeux-ai/whatsmiau/env"
 	"github.com/verbeux-ai/whatsmiau/models"
 	"go.mau.fi/whatsmeow"
@@ -54,6 +56,31 @@ func (s *Whatsmiau) getCtx(ctx context.Context, url string) (*http.Response, err
 	return res, nil
 }
 
+// fetchMedia downloads url, failing on non-2xx responses so an error page is never sent as media
+func (s *Whatsmiau) fetchMedia(ctx context.Context, url string) ([]byte, error) {
+	res, err := s.getCtx(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil, fmt.Errorf("failed to fetch media: status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// loadMedia returns the content of a media sent as URL or base64 data URI
+func (s *Whatsmiau) loadMedia(ctx context.Context, media string) ([]byte, error) {
+	if strings.HasPrefix(media, "data:") {
+		return processBase64Image(media)
+	}
+
+	return s.fetchMedia(ctx, media)
+}
+
 // processBase64Image processa imagem já codificada em base64 data URI
 // Formato: "data:image/jpeg;base64,/9j/4AAQSkZJRg..."
 // Retorna imageData, error
@@ -182,6 +209,49 @@ func extractAudioMetadata(audioData []byte) ([]byte, float64, error) {
 	return buf, durationSec, nil
 }
 
+// profilePictureSize is the side of the square JPEG WhatsApp uses for profile pictures
+const profilePictureSize = 640
+
+// resizeProfilePicture crops the image to a centered square JPEG of profilePictureSize
+func resizeProfilePicture(data []byte) ([]byte, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, errors.New("ffmpeg not found in path (install to resize images)")
+	}
+
+	tempIn, err := os.CreateTemp("", "picture-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tempIn.Name())
+	if _, err := io.Copy(tempIn, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if err := tempIn.Close(); err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command(
+		"ffmpeg",
+		"-i", tempIn.Name(),
+		"-vf", fmt.Sprintf("crop='min(iw,ih)':'min(iw,ih)',scale=%d:%d", profilePictureSize, profilePictureSize),
+		"-frames:v", "1",
+		"-q:v", "3",
+		"-f", "image2",
+		"-c:v", "mjpeg",
+		"-hide_banner",
+		"-loglevel", "error",
+		"pipe:1",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed resizing image: %w", err)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no data after resizing image")
+	}
+
+	return out, nil
+}
+
 // Returns audioConverted, waveform, duration and an error
 func convertAudio(data []byte, bars int) ([]byte, []byte, float64, error) {
 	if _, err := exec.LookPath("ffmpeg"); err != nil {
@@ -351,7 +421,9 @@ func extractMimetype(decodedData []byte, fileName string) (string, error) {
 	return detected, nil
 }
 
-func extractExtFromFile(fileName, mimeType string, file *os.File) string {
+// extractExtFromName resolves the extension using only the file name and mimetype,
+// returning an empty string when the file content must be inspected
+func extractExtFromName(fileName, mimeType string) string {
 	ext := filepath.Ext(fileName)
 	if ext == "" {
 		if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
@@ -360,25 +432,48 @@ func extractExtFromFile(fileName, mimeType string, file *os.File) string {
 			} else {
 				ext = exts[0]
 			}
-		} else {
-			buf := make([]byte, 512)
-			n, err := file.Read(buf)
-			if err != nil && err != io.EOF {
-				zap.L().Error("failed to read file", zap.Error(err))
-			}
-			detected := http.DetectContentType(buf[:n])
-			if exts, _ := mime.ExtensionsByType(detected); len(exts) > 0 {
-				ext = exts[0]
-			}
-			if _, err := file.Seek(0, io.SeekStart); err != nil {
-				zap.L().Error("failed to seek image", zap.Error(err))
-			}
 		}
 	}
 
 	return strings.TrimPrefix(ext, ".")
 }
 
+func extractExtFromFile(fileName, mimeType string, file *os.File) string {
+	ext := extractExtFromName(fileName, mimeType)
+	if ext != "" {
+		return ext
+	}
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		zap.L().Error("failed to read file", zap.Error(err))
+	}
+	detected := http.DetectContentType(buf[:n])
+	if exts, _ := mime.ExtensionsByType(detected); len(exts) > 0 {
+		ext = exts[0]
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		zap.L().Error("failed to seek image", zap.Error(err))
+	}
+
+	return strings.TrimPrefix(ext, ".")
+}
+
+// mediaObjectName builds the storage key of a media file. Files are keyed by their
+// SHA-256 so the same media received many times is stored only once
+func mediaObjectName(fileSHA256 []byte, ext string) string {
+	name := uuid.NewString()
+	if len(fileSHA256) > 0 {
+		name = hex.EncodeToString(fileSHA256)
+	}
+	if ext == "" {
+		return name
+	}
+
+	return name + "." + ext
+}
+
 func canIgnoreMessage(msg *events.Message) bool {
 	return strings.Contains(msg.Info.Chat.String(), "status")
 }
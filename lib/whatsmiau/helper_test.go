@@ -0,0 +1,49 @@
+package whatsmiau
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadMedia(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/picture.jpg":
+			_, _ = w.Write([]byte("jpeg"))
+		case "/redirect":
+			http.Redirect(w, r, "/picture.jpg", http.StatusFound)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := &Whatsmiau{httpClient: server.Client()}
+
+	tests := []struct {
+		name    string
+		media   string
+		want    string
+		wantErr bool
+	}{
+		{name: "data uri", media: "data:image/jpeg;base64,anBlZw==", want: "jpeg"},
+		{name: "invalid data uri", media: "data:image/jpeg;base64", wantErr: true},
+		{name: "url", media: server.URL + "/picture.jpg", want: "jpeg"},
+		{name: "redirected url", media: server.URL + "/redirect", want: "jpeg"},
+		{name: "error page is rejected", media: server.URL + "/missing.jpg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.loadMedia(context.Background(), tt.media)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadMedia() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("loadMedia() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
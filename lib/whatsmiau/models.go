@@ -1,20 +1,49 @@
 package whatsmiau
 
 import (
+	"strings"
 	"time"
 
 	"github.com/emersion/go-vcard"
+	"github.com/verbeux-ai/whatsmiau/models"
 )
 
 type Wook string
 
 const (
-	WookMessagesUpsert   Wook = "messages.upsert"
-	WookMessagesUpdate   Wook = "messages.update"
-	WookContactsUpsert   Wook = "contacts.upsert"
-	WookConnectionUpdate Wook = "connection.update"
+	WookMessagesUpsert          Wook = "messages.upsert"
+	WookMessagesUpdate          Wook = "messages.update"
+	WookContactsUpsert          Wook = "contacts.upsert"
+	WookConnectionUpdate        Wook = "connection.update"
+	WookSendMessage             Wook = "send.message"
+	WookGroupsUpsert            Wook = "groups.upsert"
+	WookGroupsUpdate            Wook = "groups.update"
+	WookGroupParticipantsUpdate Wook = "group-participants.update"
+	WookNewsletterMessage       Wook = "newsletter.message"
+	WookStatusUpsert            Wook = "status.upsert"
+	WookWebhookTest             Wook = "webhook.test"
 )
 
+// Kebab returns the event name used on URLs when the webhook is configured by events (e.g. messages-upsert)
+func (w Wook) Kebab() string {
+	return strings.ReplaceAll(strings.ToLower(string(w)), ".", "-")
+}
+
+// wookEvent is implemented by every WookEvent, allowing the emitter to know the event type of a payload
+type wookEvent interface {
+	EventName() Wook
+	ChatJID() string
+	envelope() wookEnvelope
+}
+
+// wookEnvelope holds the fields every payload profile is built from
+type wookEnvelope struct {
+	Event    Wook
+	Instance string
+	Data     any
+	DateTime time.Time
+}
+
 type WookEvent[data any] struct {
 	Instance    string    `json:"instance,omitempty"`
 	Data        *data     `json:"data,omitempty"`
@@ -26,6 +55,59 @@ type WookEvent[data any] struct {
 	Event       Wook      `json:"event,omitempty"`
 }
 
+func (e *WookEvent[data]) EventName() Wook {
+	return e.Event
+}
+
+func (e *WookEvent[data]) envelope() wookEnvelope {
+	result := wookEnvelope{
+		Event:    e.Event,
+		Instance: e.Instance,
+		DateTime: e.DateTime,
+	}
+	if e.Data != nil {
+		result.Data = e.Data
+	}
+
+	return result
+}
+
+// ChatJID returns the chat of message events, empty for events not related to a chat
+func (e *WookEvent[data]) ChatJID() string {
+	switch d := any(e.Data).(type) {
+	case *WookMessageData:
+		if d != nil && d.Key != nil {
+			return d.Key.RemoteJid
+		}
+	case *WookMessageUpdateData:
+		if d != nil {
+			return d.RemoteJid
+		}
+	case *WookGroupsUpsertData:
+		if d != nil && len(*d) > 0 {
+			return (*d)[0].ID
+		}
+	case *WookGroupUpdateData:
+		if d != nil && len(*d) > 0 {
+			return (*d)[0].Id
+		}
+	case *WookGroupParticipantsUpdateData:
+		if d != nil {
+			return d.Id
+		}
+	case *WookNewsletterMessageData:
+		if d != nil {
+			return d.NewsletterJid
+		}
+	}
+
+	return ""
+}
+
+type WookWebhookTestData struct {
+	Message string `json:"message,omitempty"`
+}
+
 type WookMessageData struct {
 	Key              *WookKey                `json:"key,omitempty"`
 	PushName         string                  `json:"pushName,omitempty"`
@@ -99,12 +181,23 @@ type WookMessageRaw struct {
 	ReactionMessage      *ReactionMessageRaw      `json:"reactionMessage,omitempty"`
 	ContactMessage       *ContactMessageRaw       `json:"contactMessage,omitempty"`
 	ContactsArrayMessage *ContactsArrayMessageRaw `json:"contactsArrayMessage,omitempty"`
+	GroupInviteMessage   *GroupInviteMessageRaw   `json:"groupInviteMessage,omitempty"`
 	//MessageContextInfo  WookMessageContextInfo `json:"messageContextInfo,omitempty"`
 
 	ListResponseMessage *WookListMessageRaw `json:"listResponseMessage,omitempty"`
 	MediaURL            string              `json:"mediaUrl,omitempty"` // Sent when connect with some storage
 }
 
+// GroupInviteMessageRaw is an invite sent in chat, accepted with the sender as inviter
+type GroupInviteMessageRaw struct {
+	GroupJid         string `json:"groupJid,omitempty"`
+	InviteCode       string `json:"inviteCode,omitempty"`
+	InviteExpiration string `json:"inviteExpiration,omitempty"`
+	GroupName        string `json:"groupName,omitempty"`
+	JpegThumbnail    string `json:"jpegThumbnail,omitempty"`
+	Caption          string `json:"caption,omitempty"`
+}
+
 type ContactsArrayMessageRaw struct {
 	DisplayName string              `json:"displayName,omitempty"`
 	Contacts    []ContactMessageRaw `json:"contacts,omitempty"`
@@ -242,10 +335,26 @@ type ContextInfoDisappearingMode struct {
 type WookMessageUpdateStatus string
 
 const (
+	MessageStatusError       WookMessageUpdateStatus = "ERROR"
+	MessageStatusServerAck   WookMessageUpdateStatus = "SERVER_ACK"
 	MessageStatusDeliveryAck WookMessageUpdateStatus = "DELIVERY_ACK"
 	MessageStatusRead        WookMessageUpdateStatus = "READ"
+	MessageStatusPlayed      WookMessageUpdateStatus = "PLAYED"
 )
 
+// messageStatusRank orders the statuses, a message only goes forward (ERROR is kept apart)
+var messageStatusRank = map[WookMessageUpdateStatus]int{
+	MessageStatusServerAck:   1,
+	MessageStatusDeliveryAck: 2,
+	MessageStatusRead:        3,
+	MessageStatusPlayed:      4,
+}
+
+// Rank returns the position of the status on the message lifecycle, 0 for ERROR and unknown statuses
+func (s WookMessageUpdateStatus) Rank() int {
+	return messageStatusRank[s]
+}
+
 type WookMessageUpdateData struct {
 	MessageId      string                  `json:"messageId,omitempty"`
 	KeyId          string                  `json:"keyId,omitempty"`
@@ -269,6 +378,54 @@ type WookContact struct {
 
 type WookContactUpsertData []WookContact
 
+// WookGroupsUpsertData carries the groups the instance joined (created, added or by invite)
+type WookGroupsUpsertData []models.GroupInfo
+
+type WookGroupUpdateData []WookGroupUpdate
+
+// WookGroupUpdate has only the settings that changed
+type WookGroupUpdate struct {
+	Id                string         `json:"id"`
+	Author            string         `json:"author,omitempty"`
+	Subject           *string        `json:"subject,omitempty"`
+	Desc              *string        `json:"desc,omitempty"`
+	Restrict          *bool          `json:"restrict,omitempty"`
+	Announce          *bool          `json:"announce,omitempty"`
+	EphemeralDuration *uint32        `json:"ephemeralDuration,omitempty"`
+	JoinApprovalMode  *bool          `json:"joinApprovalMode,omitempty"`
+	InviteLink        string         `json:"inviteLink,omitempty"`
+	Deleted           bool           `json:"deleted,omitempty"`
+	Linked            *WookGroupLink `json:"linked,omitempty"`   // group linked to the community
+	Unlinked          *WookGroupLink `json:"unlinked,omitempty"` // group unlinked from the community
+}
+
+type WookGroupLink struct {
+	Type                string `json:"type"` // parent_group, sub_group or sibling_group
+	Id                  string `json:"id"`
+	Subject             string `json:"subject,omitempty"`
+	IsCommunityAnnounce bool   `json:"isCommunityAnnounce,omitempty"`
+	Reason              string `json:"reason,omitempty"` // unlink reason
+}
+
+type WookGroupParticipantsUpdateData struct {
+	Id           string   `json:"id"`
+	Author       string   `json:"author,omitempty"`
+	Participants []string `json:"participants"`
+	Action       string   `json:"action"`           // add, remove, promote or demote
+	Reason       string   `json:"reason,omitempty"` // invite when joined by link
+}
+
+type WookNewsletterMessageData struct {
+	NewsletterJid    string          `json:"newsletterJid"`
+	ServerId         int             `json:"serverId"`
+	MessageId        string          `json:"messageId"`
+	MessageType      string          `json:"messageType,omitempty"`
+	Message          *WookMessageRaw `json:"message,omitempty"`
+	MessageTimestamp int             `json:"messageTimestamp,omitempty"`
+	FromMe           bool            `json:"fromMe"`
+	InstanceId       string          `json:"instanceId,omitempty"`
+}
+
 type WookConnectionUpdateData struct {
 	Status string `json:"status,omitempty"` // "open" ou "close"
 }
@@ -0,0 +1,285 @@
+package whatsmiau
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"golang.org/x/net/context"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewsletterPost is a message fetched from a channel
+type NewsletterPost struct {
+	ServerID    int             `json:"serverId"`
+	MessageID   string          `json:"messageId"`
+	MessageType string          `json:"messageType"`
+	Message     *WookMessageRaw `json:"message,omitempty"`
+	Views       int             `json:"views"`
+	Reactions   map[string]int  `json:"reactions,omitempty"`
+	Timestamp   time.Time       `json:"timestamp"`
+}
+
+func convertNewsletter(meta *types.NewsletterMetadata) *models.Newsletter {
+	result := &models.Newsletter{
+		ID:           meta.ID.String(),
+		Name:         meta.ThreadMeta.Name.Text,
+		Description:  meta.ThreadMeta.Description.Text,
+		InviteCode:   meta.ThreadMeta.InviteCode,
+		Subscribers:  meta.ThreadMeta.SubscriberCount,
+		Verification: string(meta.ThreadMeta.VerificationState),
+		State:        string(meta.State.Type),
+		CreatedAt:    meta.ThreadMeta.CreationTime.Time,
+	}
+
+	if meta.ThreadMeta.Picture != nil {
+		result.PictureURL = meta.ThreadMeta.Picture.URL
+	}
+	if meta.ViewerMeta != nil {
+		result.Role = string(meta.ViewerMeta.Role)
+		result.Muted = meta.ViewerMeta.Mute == types.NewsletterMuteOn
+	}
+
+	return result
+}
+
+type CreateNewsletterRequest struct {
+	InstanceID  string `json:"instance_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Picture     string `json:"picture"` // URL or base64 data URI
+}
+
+func (s *Whatsmiau) CreateNewsletter(ctx context.Context, data *CreateNewsletterRequest) (*models.Newsletter, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	params := whatsmeow.CreateNewsletterParams{
+		Name:        data.Name,
+		Description: data.Description,
+	}
+
+	if len(data.Picture) > 0 {
+		picture, err := s.loadMedia(ctx, data.Picture)
+		if err != nil {
+			return nil, err
+		}
+		params.Picture = picture
+	}
+
+	meta, err := client.CreateNewsletter(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertNewsletter(meta), nil
+}
+
+// NewsletterInfo returns the channel by jid or, when jid is nil, by invite code
+func (s *Whatsmiau) NewsletterInfo(ctx context.Context, instanceID string, jid *types.JID, inviteCode string) (*models.Newsletter, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	var meta *types.NewsletterMetadata
+	var err error
+	if jid != nil {
+		meta, err = client.GetNewsletterInfo(ctx, *jid)
+	} else {
+		meta, err = client.GetNewsletterInfoWithInvite(ctx, inviteCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return convertNewsletter(meta), nil
+}
+
+// ListNewsletters returns the channels the instance follows or owns
+func (s *Whatsmiau) ListNewsletters(ctx context.Context, instanceID string) ([]models.Newsletter, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	newsletters, err := client.GetSubscribedNewsletters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Newsletter, 0, len(newsletters))
+	for _, meta := range newsletters {
+		result = append(result, *convertNewsletter(meta))
+	}
+
+	return result, nil
+}
+
+// NewsletterMessages returns the last count messages of the channel, before the given server id when not zero
+func (s *Whatsmiau) NewsletterMessages(ctx context.Context, instanceID string, jid types.JID, count, before int) ([]NewsletterPost, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	messages, err := client.GetNewsletterMessages(ctx, jid, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  count,
+		Before: types.MessageServerID(before),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]NewsletterPost, 0, len(messages))
+	for _, message := range messages {
+		post := NewsletterPost{
+			ServerID:    int(message.MessageServerID),
+			MessageID:   message.MessageID,
+			MessageType: message.Type,
+			Views:       message.ViewsCount,
+			Reactions:   message.ReactionCounts,
+			Timestamp:   message.Timestamp,
+		}
+		if message.Message != nil {
+			post.MessageType, post.Message, _ = s.parseWAMessage(message.Message)
+		}
+
+		result = append(result, post)
+	}
+
+	return result, nil
+}
+
+// FollowNewsletter follows the channel, or unfollows it when follow is false
+func (s *Whatsmiau) FollowNewsletter(ctx context.Context, instanceID string, jid types.JID, follow bool) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	if follow {
+		return client.FollowNewsletter(ctx, jid)
+	}
+
+	return client.UnfollowNewsletter(ctx, jid)
+}
+
+func (s *Whatsmiau) MuteNewsletter(ctx context.Context, instanceID string, jid types.JID, mute bool) error {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return whatsmeow.ErrClientIsNil
+	}
+
+	return client.NewsletterToggleMute(ctx, jid, mute)
+}
+
+type SendNewsletterRequest struct {
+	InstanceID    string     `json:"instance_id"`
+	NewsletterJID *types.JID `json:"newsletter_jid"`
+	Text          string     `json:"text"`
+	MediaType     string     `json:"media_type"` // image, video or document, empty for text
+	Media         string     `json:"media"`      // URL or base64 data URI
+	Caption       string     `json:"caption"`
+	FileName      string     `json:"file_name"`
+	Mimetype      string     `json:"mimetype"`
+}
+
+type SendNewsletterResponse struct {
+	ID        string    `json:"id"`
+	ServerID  int       `json:"server_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SendNewsletter posts a text or media update on a channel the instance administers. Channel media is not
+// encrypted, so it is uploaded with UploadNewsletter and sent with its media handle.
+func (s *Whatsmiau) SendNewsletter(ctx context.Context, data *SendNewsletterRequest) (*SendNewsletterResponse, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	message := &waE2E.Message{}
+	var extra whatsmeow.SendRequestExtra
+	if data.MediaType == "" {
+		message.Conversation = proto.String(data.Text)
+	} else {
+		media, err := s.loadMedia(ctx, data.Media)
+		if err != nil {
+			return nil, err
+		}
+
+		mimetype := data.Mimetype
+		if mimetype == "" {
+			mimetype, _ = extractMimetype(media, data.FileName)
+		}
+
+		var mediaType whatsmeow.MediaType
+		switch data.MediaType {
+		case "image":
+			mediaType = whatsmeow.MediaImage
+		case "video":
+			mediaType = whatsmeow.MediaVideo
+		case "document":
+			mediaType = whatsmeow.MediaDocument
+		default:
+			return nil, fmt.Errorf("invalid media type: %s", data.MediaType)
+		}
+
+		uploaded, err := client.UploadNewsletter(ctx, media, mediaType)
+		if err != nil {
+			return nil, err
+		}
+		extra.MediaHandle = uploaded.Handle
+
+		switch data.MediaType {
+		case "image":
+			message.ImageMessage = &waE2E.ImageMessage{
+				URL:        proto.String(uploaded.URL),
+				DirectPath: proto.String(uploaded.DirectPath),
+				Mimetype:   proto.String(mimetype),
+				Caption:    proto.String(data.Caption),
+				FileSHA256: uploaded.FileSHA256,
+				FileLength: proto.Uint64(uploaded.FileLength),
+			}
+		case "video":
+			message.VideoMessage = &waE2E.VideoMessage{
+				URL:        proto.String(uploaded.URL),
+				DirectPath: proto.String(uploaded.DirectPath),
+				Mimetype:   proto.String(mimetype),
+				Caption:    proto.String(data.Caption),
+				FileSHA256: uploaded.FileSHA256,
+				FileLength: proto.Uint64(uploaded.FileLength),
+			}
+		case "document":
+			message.DocumentMessage = &waE2E.DocumentMessage{
+				URL:        proto.String(uploaded.URL),
+				DirectPath: proto.String(uploaded.DirectPath),
+				Mimetype:   proto.String(mimetype),
+				Caption:    proto.String(data.Caption),
+				FileName:   proto.String(data.FileName),
+				FileSHA256: uploaded.FileSHA256,
+				FileLength: proto.Uint64(uploaded.FileLength),
+			}
+		}
+	}
+
+	res, err := client.SendMessage(ctx, *data.NewsletterJID, message, extra)
+	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.NewsletterJID, res)
+		return nil, err
+	}
+
+	go s.handleSentMessage(data.InstanceID, *data.NewsletterJID, res, message)
+
+	return &SendNewsletterResponse{
+		ID:        res.ID,
+		ServerID:  int(res.ServerID),
+		CreatedAt: res.Timestamp,
+	}, nil
+}
@@ -0,0 +1,413 @@
+package whatsmiau
+
+import (
+	"errors"
+	"hash/fnv"
+	"os"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"github.com/verbeux-ai/whatsmiau/repositories/webhooks"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const outboxReadCount = 64
+
+const (
+	EmitterPolicyBlock      = "block"
+	EmitterPolicyDropOldest = "drop-oldest"
+	EmitterPolicySpill      = "spill"
+)
+
+// enqueue hands the delivery to the emitter following EMITTER_FULL_POLICY when the buffer is full
+func (s *Whatsmiau) enqueue(delivery *models.WebhookDelivery) {
+	switch env.Env.EmitterFullPolicy {
+	case EmitterPolicyDropOldest:
+		for {
+			select {
+			case s.emitter <- delivery:
+				return
+			default:
+			}
+
+			select {
+			case dropped := <-s.emitter:
+				zap.L().Warn("emitter buffer full, dropping oldest event",
+					zap.String("instance", dropped.InstanceID),
+					zap.String("event", dropped.Event),
+					zap.String("delivery", dropped.ID))
+			default:
+			}
+		}
+	case EmitterPolicySpill:
+		// older events still on disk go first
+		if s.spill.WriteIfActive(delivery) {
+			return
+		}
+
+		select {
+		case s.emitter <- delivery:
+		default:
+			s.spill.Write(delivery)
+		}
+	default:
+		s.emitter <- delivery
+	}
+}
+
+// startEmitter persists the emitted events on the outbox, keeping the whatsmeow handlers away from redis latency
+func (s *Whatsmiau) startEmitter() {
+	for delivery := range s.emitter {
+		s.pushOutbox(delivery)
+	}
+}
+
+func (s *Whatsmiau) pushOutbox(delivery *models.WebhookDelivery) {
+	for {
+		ctx, c := context.WithTimeout(context.Background(), 5*time.Second)
+		err := s.outbox.Push(ctx, delivery)
+		c()
+		if err == nil {
+			return
+		}
+
+		if errors.Is(err, webhooks.ErrOutboxFull) {
+			// nothing is trimmed: the emitter holds the events (EMITTER_FULL_POLICY) until deliveries catch up
+			zap.L().Error("webhook outbox reached WEBHOOK_OUTBOX_MAX_LEN, holding events until deliveries catch up",
+				zap.String("instance", delivery.InstanceID), zap.Int64("maxLen", env.Env.WebhookOutboxMaxLen))
+		} else {
+			zap.L().Error("failed to persist webhook on outbox, retrying", zap.String("instance", delivery.InstanceID), zap.Error(err))
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// startOutboxConsumer reads the outbox and dispatches the deliveries to WEBHOOK_WORKERS workers.
+// Deliveries are partitioned by instance (or chat), so a slow receiver only holds its own partition
+// and events of the same partition keep their order. The consumer never waits on a worker: when its
+// queue is full the partition is deferred and picked up again from the pending entries. It also takes
+// over deliveries left pending by a crash or restart.
+func (s *Whatsmiau) startOutboxConsumer() {
+	workers := env.Env.WebhookWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	s.inflight = xsync.NewMap[string, bool]()
+	s.deferred = make(map[string]bool)
+	s.deferredIDs = make(map[string]bool)
+	s.shards = make([]chan *models.WebhookDelivery, workers)
+	for i := range s.shards {
+		s.shards[i] = make(chan *models.WebhookDelivery, outboxReadCount)
+		go s.startDeliveryWorker(s.shards[i])
+	}
+
+	consumer := outboxConsumerName()
+	touchEvery := max(env.Env.WebhookOutboxClaimIdle/3, time.Second)
+	lastClaim := time.Time{}
+	lastRedispatch := time.Time{}
+	lastTouch := time.Now()
+
+	for {
+		if time.Since(lastTouch) >= touchEvery {
+			lastTouch = time.Now()
+			s.touchHeld(consumer)
+		}
+
+		if time.Since(lastClaim) >= time.Minute {
+			lastClaim = time.Now()
+			s.claimOutbox(consumer)
+		}
+
+		if len(s.deferred) > 0 && time.Since(lastRedispatch) >= time.Second {
+			lastRedispatch = time.Now()
+			s.redispatchDeferred(consumer)
+		}
+
+		ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+		deliveries, err := s.outbox.Read(ctx, consumer, outboxReadCount, 5*time.Second)
+		c()
+		if err != nil {
+			zap.L().Error("failed to read webhook outbox", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for i := range deliveries {
+			s.dispatch(&deliveries[i])
+		}
+	}
+}
+
+func (s *Whatsmiau) startDeliveryWorker(shard chan *models.WebhookDelivery) {
+	batches := newWebhookBatches()
+	ticker := time.NewTicker(batchTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case delivery := <-shard:
+			if delivery.Batch == nil || delivery.Batch.Size <= 1 {
+				s.processDelivery(delivery)
+				s.inflight.Delete(delivery.StreamID)
+				continue
+			}
+
+			if full := batches.add(delivery); full != nil {
+				s.processBatch(full)
+			}
+		case now := <-ticker.C:
+			for _, batch := range batches.due(now) {
+				s.processBatch(batch)
+			}
+		}
+	}
+}
+
+func deliveryPartition(delivery *models.WebhookDelivery) string {
+	if delivery.Partition == "" {
+		return delivery.InstanceID
+	}
+
+	return delivery.Partition
+}
+
+// dispatch hands the delivery to the worker of its partition. When the worker queue is full the delivery
+// is left pending on the outbox and the partition is deferred: its next deliveries are left pending too,
+// keeping their order, until redispatchDeferred hands them over.
+func (s *Whatsmiau) dispatch(delivery *models.WebhookDelivery) {
+	partition := deliveryPartition(delivery)
+	if s.deferred[partition] {
+		s.deferredIDs[delivery.StreamID] = true
+		return
+	}
+
+	if !s.tryDispatch(delivery, partition) {
+		s.deferred[partition] = true
+		s.deferredIDs[delivery.StreamID] = true
+	}
+}
+
+// tryDispatch returns false when the worker queue of the partition is full
+func (s *Whatsmiau) tryDispatch(delivery *models.WebhookDelivery, partition string) bool {
+	// claimed deliveries may still be waiting on a worker
+	if _, loaded := s.inflight.LoadOrStore(delivery.StreamID, true); loaded {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(partition))
+
+	select {
+	case s.shards[h.Sum32()%uint32(len(s.shards))] <- delivery:
+		return true
+	default:
+		s.inflight.Delete(delivery.StreamID)
+		return false
+	}
+}
+
+// redispatchDeferred goes through the deliveries pending on this consumer, oldest first, handing the ones
+// of deferred partitions to their workers. A partition is resumed once all of its deliveries were handed over.
+func (s *Whatsmiau) redispatchDeferred(consumer string) {
+	stillDeferred := make(map[string]bool)
+	after := ""
+
+	for len(stillDeferred) < len(s.deferred) {
+		ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+		deliveries, err := s.outbox.ReadPending(ctx, consumer, after, outboxReadCount)
+		c()
+		if err != nil {
+			zap.L().Error("failed to read pending webhooks", zap.Error(err))
+			return
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			after = delivery.StreamID
+
+			partition := deliveryPartition(delivery)
+			if !s.deferred[partition] || stillDeferred[partition] {
+				continue
+			}
+
+			if !s.tryDispatch(delivery, partition) {
+				stillDeferred[partition] = true
+				continue
+			}
+			delete(s.deferredIDs, delivery.StreamID)
+		}
+	}
+
+	s.deferred = stillDeferred
+	if len(s.deferred) == 0 {
+		clear(s.deferredIDs)
+	}
+}
+
+// touchHeld resets the idle time of the deliveries this replica holds: waiting on a worker, being sent or
+// left pending on a deferred partition. Without it a backlog older than WEBHOOK_OUTBOX_CLAIM_IDLE would
+// be claimed by another replica and sent twice, out of order.
+func (s *Whatsmiau) touchHeld(consumer string) {
+	ids := make([]string, 0, s.inflight.Size()+len(s.deferredIDs))
+	s.inflight.Range(func(id string, _ bool) bool {
+		ids = append(ids, id)
+		return true
+	})
+	for id := range s.deferredIDs {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+	defer c()
+
+	if err := s.outbox.Touch(ctx, consumer, ids); err != nil {
+		zap.L().Error("failed to refresh held webhooks", zap.Int("total", len(ids)), zap.Error(err))
+	}
+}
+
+func (s *Whatsmiau) claimOutbox(consumer string) {
+	ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+	defer c()
+
+	deliveries, err := s.outbox.Claim(ctx, consumer, env.Env.WebhookOutboxClaimIdle, outboxReadCount)
+	if err != nil {
+		zap.L().Error("failed to claim pending webhooks", zap.Error(err))
+		return
+	}
+
+	if len(deliveries) > 0 {
+		zap.L().Info("recovering pending webhooks", zap.Int("total", len(deliveries)))
+	}
+
+	for i := range deliveries {
+		s.dispatch(&deliveries[i])
+	}
+}
+
+// processDelivery sends the webhook (or publishes on the broker) and finishes it. The reply actions
+// returned by the webhook only start after the delivery is recorded, away from the worker.
+func (s *Whatsmiau) processDelivery(delivery *models.WebhookDelivery) {
+	var (
+		response *webhookResponse
+		sendErr  error
+	)
+	if delivery.Sink == "" {
+		s.webhookCredentials(delivery)
+		response, sendErr = s.sendWebhookWithRetry(delivery)
+	} else {
+		sendErr = s.publishWithRetry(delivery)
+	}
+
+	s.finishDelivery(delivery, sendErr)
+
+	if response != nil {
+		s.startReplyActions(delivery, response)
+	}
+}
+
+// finishDelivery removes the delivery from the outbox, moving it to the dead-letter list when every retry
+// failed, or to the retry queue when its host circuit is open
+func (s *Whatsmiau) finishDelivery(delivery *models.WebhookDelivery, sendErr error) {
+	ctx, c := context.WithTimeout(context.Background(), 5*time.Second)
+	defer c()
+
+	if errors.Is(sendErr, errCircuitOpen) && time.Since(delivery.CreatedAt) < env.Env.WebhookBreakerMaxPark {
+		if err := s.outbox.Park(ctx, delivery, time.Now().Add(env.Env.WebhookBreakerCooldown)); err != nil {
+			zap.L().Error("failed to park webhook", zap.String("instance", delivery.InstanceID), zap.String("delivery", delivery.ID), zap.Error(err))
+		}
+		return
+	}
+
+	s.logDelivery(ctx, delivery, sendErr)
+
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+		if err := s.outbox.DeadLetter(ctx, delivery); err != nil {
+			zap.L().Error("failed to dead-letter webhook", zap.String("instance", delivery.InstanceID), zap.String("delivery", delivery.ID), zap.Error(err))
+		}
+		return
+	}
+
+	if err := s.outbox.Ack(ctx, delivery); err != nil {
+		zap.L().Error("failed to ack webhook", zap.String("instance", delivery.InstanceID), zap.String("delivery", delivery.ID), zap.Error(err))
+	}
+}
+
+func (s *Whatsmiau) logDelivery(ctx context.Context, delivery *models.WebhookDelivery, sendErr error) {
+	entry := &models.WebhookDeliveryLog{
+		ID:         delivery.ID,
+		WebhookID:  delivery.WebhookID,
+		Event:      delivery.Event,
+		Sink:       delivery.Sink,
+		URL:        delivery.URL,
+		Attempts:   delivery.Attempts,
+		StatusCode: delivery.StatusCode,
+		LatencyMs:  delivery.LatencyMs,
+		Response:   delivery.Response,
+		Success:    sendErr == nil,
+		CreatedAt:  time.Now(),
+	}
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+
+	if err := s.deliveryLog.Add(ctx, delivery.InstanceID, entry); err != nil {
+		zap.L().Error("failed to log webhook delivery", zap.String("instance", delivery.InstanceID), zap.String("delivery", delivery.ID), zap.Error(err))
+	}
+}
+
+// startRetryQueue moves back to the outbox the deliveries parked while their host circuit was open
+func (s *Whatsmiau) startRetryQueue() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if total := s.unparkDue(now); total > 0 {
+			zap.L().Debug("parked webhooks moved back to outbox", zap.Int("total", total))
+		}
+	}
+}
+
+// unparkDue moves every parked delivery due at now, page by page, so a burst parked by an open
+// circuit is not released at outboxReadCount per second
+func (s *Whatsmiau) unparkDue(now time.Time) int {
+	total := 0
+	for {
+		ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+		moved, err := s.outbox.Unpark(ctx, now, outboxReadCount)
+		c()
+		total += moved
+		if err != nil {
+			zap.L().Error("failed to unpark webhooks", zap.Error(err))
+			return total
+		}
+
+		// a short page means nothing else is due (or the outbox is full)
+		if moved < outboxReadCount {
+			return total
+		}
+	}
+}
+
+func outboxConsumerName() string {
+	if env.Env.WebhookOutboxConsumer != "" {
+		return env.Env.WebhookOutboxConsumer
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "whatsmiau"
+	}
+
+	return hostname
+}
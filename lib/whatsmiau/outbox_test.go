@@ -0,0 +1,117 @@
+package whatsmiau
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+// pendingOutbox serves the pending history of the consumer and records the touched stream ids
+type pendingOutbox struct {
+	interfaces.WebhookOutbox
+
+	pending []models.WebhookDelivery
+	touched []string
+}
+
+func (o *pendingOutbox) ReadPending(_ context.Context, _ string, after string, count int64) ([]models.WebhookDelivery, error) {
+	var result []models.WebhookDelivery
+	for _, delivery := range o.pending {
+		if delivery.StreamID > after && int64(len(result)) < count {
+			result = append(result, delivery)
+		}
+	}
+
+	return result, nil
+}
+
+func (o *pendingOutbox) Touch(_ context.Context, _ string, streamIDs []string) error {
+	o.touched = append([]string(nil), streamIDs...)
+	slices.Sort(o.touched)
+	return nil
+}
+
+func TestTouchHeldDeliveries(t *testing.T) {
+	outbox := &pendingOutbox{}
+	s := &Whatsmiau{
+		outbox:      outbox,
+		inflight:    xsync.NewMap[string, bool](),
+		deferred:    make(map[string]bool),
+		deferredIDs: make(map[string]bool),
+		// a single worker queue with room for one delivery
+		shards: []chan *models.WebhookDelivery{make(chan *models.WebhookDelivery, 1)},
+	}
+
+	deliveries := []models.WebhookDelivery{
+		{StreamID: "1-0", InstanceID: "a"},
+		{StreamID: "2-0", InstanceID: "a"},
+		{StreamID: "3-0", InstanceID: "b"},
+	}
+	outbox.pending = deliveries
+
+	steps := []struct {
+		name        string
+		run         func()
+		wantTouched []string
+		wantQueued  string // stream id on the worker queue after the step
+	}{
+		{
+			name: "queued and deferred deliveries are held",
+			run: func() {
+				for i := range deliveries {
+					s.dispatch(&deliveries[i])
+				}
+			},
+			wantTouched: []string{"1-0", "2-0", "3-0"},
+			wantQueued:  "1-0",
+		},
+		{
+			name: "a delivery handed to the worker stays held until it is sent",
+			run: func() {
+				<-s.shards[0]
+				s.redispatchDeferred("consumer")
+			},
+			wantTouched: []string{"1-0", "2-0", "3-0"},
+			wantQueued:  "2-0",
+		},
+		{
+			name: "sent deliveries are released",
+			run: func() {
+				s.inflight.Delete("1-0")
+				s.inflight.Delete((<-s.shards[0]).StreamID)
+				outbox.pending = deliveries[2:]
+				s.redispatchDeferred("consumer")
+			},
+			wantTouched: []string{"3-0"},
+			wantQueued:  "3-0",
+		},
+	}
+
+	for _, step := range steps {
+		step.run()
+
+		outbox.touched = nil
+		s.touchHeld("consumer")
+		if !slices.Equal(outbox.touched, step.wantTouched) {
+			t.Fatalf("%s: touched %v, want %v", step.name, outbox.touched, step.wantTouched)
+		}
+
+		select {
+		case delivery := <-s.shards[0]:
+			if delivery.StreamID != step.wantQueued {
+				t.Fatalf("%s: queued %s, want %s", step.name, delivery.StreamID, step.wantQueued)
+			}
+			s.shards[0] <- delivery
+		default:
+			t.Fatalf("%s: nothing queued, want %s", step.name, step.wantQueued)
+		}
+	}
+
+	if len(s.deferred) != 0 || len(s.deferredIDs) != 0 {
+		t.Errorf("deferred = %v, %v, want none", s.deferred, s.deferredIDs)
+	}
+}
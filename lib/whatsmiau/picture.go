@@ -0,0 +1,113 @@
+package whatsmiau
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"golang.org/x/net/context"
+)
+
+// profilePicture is the cached picture of a jid, empty when it has no picture or hides it
+type profilePicture struct {
+	ID  string
+	URL string
+}
+
+func pictureKey(instanceID string, jid types.JID, preview bool) string {
+	return fmt.Sprintf("picture_%s_%s_%t", instanceID, jid.ToNonAD().String(), preview)
+}
+
+func pictureDataKey(jid types.JID, pictureID string, preview bool) string {
+	return fmt.Sprintf("picture_data_%s_%s_%t", jid.ToNonAD().String(), pictureID, preview)
+}
+
+// profilePicture returns the picture of jid, querying WhatsApp only when the cached one expired (PROFILE_CACHE_TTL)
+func (s *Whatsmiau) profilePicture(ctx context.Context, instanceID string, jid types.JID, preview bool) (*profilePicture, error) {
+	key := pictureKey(instanceID, jid, preview)
+	if cached, ok := s.pictures.Get(key); ok {
+		return cached.(*profilePicture), nil
+	}
+
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	_, isCommunity := s.communities.Load(jid.String())
+	info, err := client.GetProfilePictureInfo(ctx, jid, &whatsmeow.GetProfilePictureParams{
+		Preview:     preview,
+		IsCommunity: isCommunity,
+	})
+	if err != nil && !errors.Is(err, whatsmeow.ErrProfilePictureNotSet) && !errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized) {
+		return nil, err
+	}
+
+	result := &profilePicture{}
+	if err == nil && info != nil {
+		result.ID = info.ID
+		result.URL = info.URL
+	}
+
+	s.pictures.Set(key, result, env.Env.ProfileCacheTTL)
+	return result, nil
+}
+
+// pictureData downloads the picture of jid once per picture ID, so an unchanged picture is never downloaded again
+func (s *Whatsmiau) pictureData(ctx context.Context, jid types.JID, picture *profilePicture, preview bool) (string, error) {
+	if picture.URL == "" {
+		return "", nil
+	}
+
+	key := pictureDataKey(jid, picture.ID, preview)
+	if cached, ok := s.pictures.Get(key); ok {
+		return cached.(string), nil
+	}
+
+	raw, err := s.fetchMedia(ctx, picture.URL)
+	if err != nil {
+		return "", err
+	}
+
+	data := base64.StdEncoding.EncodeToString(raw)
+	s.pictures.Set(key, data, env.Env.ProfileCacheTTL)
+
+	return data, nil
+}
+
+// forgetPicture drops the cached picture of jid, used when WhatsApp notifies that it changed
+func (s *Whatsmiau) forgetPicture(instanceID string, jid types.JID) {
+	s.pictures.Delete(pictureKey(instanceID, jid, true))
+	s.pictures.Delete(pictureKey(instanceID, jid, false))
+}
+
+type ProfilePictureResponse struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+// FetchProfilePicture returns the full-size or preview picture of jid, with its content when withData is true
+func (s *Whatsmiau) FetchProfilePicture(ctx context.Context, instanceID string, jid types.JID, preview, withData bool) (*ProfilePictureResponse, error) {
+	picture, err := s.profilePicture(ctx, instanceID, jid, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProfilePictureResponse{
+		ID:  picture.ID,
+		URL: picture.URL,
+	}
+
+	if withData {
+		result.Base64, err = s.pictureData(ctx, jid, picture, preview)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
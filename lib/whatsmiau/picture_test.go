@@ -0,0 +1,86 @@
+package whatsmiau
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"go.mau.fi/whatsmeow/types"
+	"golang.org/x/net/context"
+)
+
+func TestPictureDataKey(t *testing.T) {
+	contact := types.NewJID("5511988887777", types.DefaultUserServer)
+	device := contact
+	device.Device = 12
+	other := types.NewJID("5511977776666", types.DefaultUserServer)
+
+	tests := []struct {
+		name      string
+		a, b      types.JID
+		previewA  bool
+		previewB  bool
+		wantEqual bool
+	}{
+		{name: "same jid", a: contact, b: contact, wantEqual: true},
+		{name: "devices of the same jid", a: contact, b: device, wantEqual: true},
+		{name: "other jid", a: contact, b: other, wantEqual: false},
+		{name: "preview", a: contact, b: contact, previewA: true, wantEqual: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := pictureDataKey(tt.a, "1735830245", tt.previewA)
+			b := pictureDataKey(tt.b, "1735830245", tt.previewB)
+			if (a == b) != tt.wantEqual {
+				t.Errorf("pictureDataKey() = %q and %q, want equal %t", a, b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestPictureDataPerJID(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.ProfileCacheTTL = time.Minute
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, _ = w.Write([]byte("picture of " + r.URL.Path[1:]))
+	}))
+	defer server.Close()
+
+	s := &Whatsmiau{httpClient: server.Client(), pictures: cache.New(time.Minute, time.Minute)}
+	contacts := []struct {
+		jid  types.JID
+		name string
+	}{
+		{jid: types.NewJID("5511988887777", types.DefaultUserServer), name: "maria"},
+		{jid: types.NewJID("5511977776666", types.DefaultUserServer), name: "joao"},
+	}
+
+	// both contacts have a picture with the same id, fetched twice to hit the cache
+	for range 2 {
+		for _, contact := range contacts {
+			picture := &profilePicture{ID: "1735830245", URL: server.URL + "/" + contact.name}
+			data, err := s.pictureData(context.Background(), contact.jid, picture, true)
+			if err != nil {
+				t.Fatalf("pictureData(%s) error = %v", contact.jid, err)
+			}
+
+			got, _ := base64.StdEncoding.DecodeString(data)
+			if want := "picture of " + contact.name; string(got) != want {
+				t.Fatalf("pictureData(%s) = %q, want %q", contact.jid, got, want)
+			}
+		}
+	}
+
+	if downloads != len(contacts) {
+		t.Errorf("downloads = %d, want %d", downloads, len(contacts))
+	}
+}
@@ -0,0 +1,119 @@
+package whatsmiau
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+// privacyWorkers limits the instances updated at the same time by ApplyPrivacySettings
+const privacyWorkers = 20
+
+func convertPrivacySettings(settings *types.PrivacySettings) *models.PrivacySettings {
+	return &models.PrivacySettings{
+		ReadReceipts: string(settings.ReadReceipts),
+		Profile:      string(settings.Profile),
+		Status:       string(settings.Status),
+		Online:       string(settings.Online),
+		Last:         string(settings.LastSeen),
+		GroupAdd:     string(settings.GroupAdd),
+		CallAdd:      string(settings.CallAdd),
+	}
+}
+
+// FetchPrivacySettings returns the privacy settings of the connected number, always queried from WhatsApp
+func (s *Whatsmiau) FetchPrivacySettings(ctx context.Context, instanceID string) (*models.PrivacySettings, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	settings, err := client.TryFetchPrivacySettings(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPrivacySettings(settings), nil
+}
+
+// UpdatePrivacySettings changes every non-empty setting, one query each, and returns the resulting settings
+func (s *Whatsmiau) UpdatePrivacySettings(ctx context.Context, instanceID string, data *models.PrivacySettings) (*models.PrivacySettings, error) {
+	client, ok := s.clients.Load(instanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	changes := []struct {
+		name  types.PrivacySettingType
+		value string
+	}{
+		{types.PrivacySettingTypeReadReceipts, data.ReadReceipts},
+		{types.PrivacySettingTypeProfile, data.Profile},
+		{types.PrivacySettingTypeStatus, data.Status},
+		{types.PrivacySettingTypeOnline, data.Online},
+		{types.PrivacySettingTypeLastSeen, data.Last},
+		{types.PrivacySettingTypeGroupAdd, data.GroupAdd},
+		{types.PrivacySettingTypeCallAdd, data.CallAdd},
+	}
+
+	for _, change := range changes {
+		if change.value == "" {
+			continue
+		}
+
+		if _, err := client.SetPrivacySetting(ctx, change.name, types.PrivacySetting(change.value)); err != nil {
+			return nil, err
+		}
+	}
+
+	// SetPrivacySetting keeps the cache updated, so no extra query is needed
+	settings, err := client.TryFetchPrivacySettings(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPrivacySettings(settings), nil
+}
+
+// ApplyPrivacySettings updates the privacy settings of every connected instance, returning one result per instance
+func (s *Whatsmiau) ApplyPrivacySettings(ctx context.Context, data *models.PrivacySettings) []models.PrivacyResult {
+	var instanceIDs []string
+	s.clients.Range(func(id string, client *whatsmeow.Client) bool {
+		if client != nil && client.IsConnected() && client.IsLoggedIn() {
+			instanceIDs = append(instanceIDs, id)
+		}
+		return true
+	})
+	sort.Strings(instanceIDs)
+
+	results := make([]models.PrivacyResult, len(instanceIDs))
+	semaphore := make(chan struct{}, privacyWorkers)
+	var wg sync.WaitGroup
+
+	for i, id := range instanceIDs {
+		wg.Add(1)
+		go func(i int, instanceID string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i].Instance = instanceID
+			settings, err := s.UpdatePrivacySettings(ctx, instanceID, data)
+			if err != nil {
+				zap.L().Error("failed to apply privacy settings", zap.String("instance", instanceID), zap.Error(err))
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Settings = settings
+		}(i, id)
+	}
+
+	wg.Wait()
+	return results
+}
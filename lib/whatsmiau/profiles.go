@@ -0,0 +1,182 @@
+package whatsmiau
+
+import (
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Payload profiles, selected per webhook
+const (
+	ProfileEvolutionV2 = "evolution-v2"
+	ProfileEvolutionV1 = "evolution-v1"
+	ProfileCompact     = "compact"
+)
+
+// evolutionEnvelope is the webhook body sent by Evolution API, every field is always present
+type evolutionEnvelope struct {
+	Event       Wook   `json:"event"`
+	Instance    string `json:"instance"`
+	Data        any    `json:"data"`
+	Destination string `json:"destination"`
+	DateTime    string `json:"date_time"`
+	Sender      string `json:"sender"`
+	ServerUrl   string `json:"server_url"`
+	Apikey      string `json:"apikey"`
+}
+
+// evolutionV2Message is the message data of Evolution v2: messageTimestamp (unix seconds), instanceId and
+// the other fields are always present
+type evolutionV2Message struct {
+	Key              *WookKey                `json:"key"`
+	PushName         string                  `json:"pushName"`
+	Status           string                  `json:"status,omitempty"`
+	Message          *WookMessageRaw         `json:"message"`
+	ContextInfo      *WookMessageContextInfo `json:"contextInfo"`
+	MessageType      string                  `json:"messageType"`
+	MessageTimestamp int                     `json:"messageTimestamp"`
+	InstanceId       string                  `json:"instanceId"`
+	Source           string                  `json:"source"`
+}
+
+// evolutionV1Message is the message data of Evolution v1: the instance goes on owner, and there is no
+// instanceId, status or contextInfo
+type evolutionV1Message struct {
+	Key              *WookKey        `json:"key"`
+	PushName         string          `json:"pushName"`
+	Message          *WookMessageRaw `json:"message"`
+	MessageType      string          `json:"messageType"`
+	MessageTimestamp int             `json:"messageTimestamp"`
+	Owner            string          `json:"owner"`
+	Source           string          `json:"source"`
+}
+
+// evolutionV1MessageUpdate is the messages.update data of Evolution v1: the message key fields, the status,
+// datetime (unix milliseconds) and owner
+type evolutionV1MessageUpdate struct {
+	RemoteJid   string                  `json:"remoteJid"`
+	FromMe      bool                    `json:"fromMe"`
+	Id          string                  `json:"id"`
+	Participant string                  `json:"participant,omitempty"`
+	Status      WookMessageUpdateStatus `json:"status"`
+	Datetime    int64                   `json:"datetime"`
+	Owner       string                  `json:"owner"`
+}
+
+// compactEvent is the native format: no envelope fields besides the event, instance and unix timestamp (ms)
+type compactEvent struct {
+	Event     Wook   `json:"event"`
+	Instance  string `json:"instance"`
+	Timestamp int64  `json:"timestamp"`
+	Data      any    `json:"data,omitempty"`
+}
+
+func webhookProfile(webhook *models.InstanceWebhook) string {
+	if webhook.Profile != "" {
+		return webhook.Profile
+	}
+	if env.Env.DefaultWebhookProfile != "" {
+		return env.Env.DefaultWebhookProfile
+	}
+
+	return ProfileEvolutionV2
+}
+
+// renderPayload shapes the event following the webhook profile. Evolution v2 sends date_time in UTC with
+// milliseconds, Evolution v1 in the server timezone without them; the message data also changes, see profileData.
+func renderPayload(body any, profile string, instance *models.Instance, destination string) any {
+	evt, ok := body.(wookEvent)
+	if !ok {
+		return body
+	}
+
+	envelope := evt.envelope()
+	if profile == ProfileCompact {
+		return &compactEvent{
+			Event:     envelope.Event,
+			Instance:  envelope.Instance,
+			Timestamp: envelope.DateTime.UnixMilli(),
+			Data:      envelope.Data,
+		}
+	}
+
+	result := &evolutionEnvelope{
+		Event:       envelope.Event,
+		Instance:    envelope.Instance,
+		Data:        profileData(envelope, profile),
+		Destination: destination,
+		Sender:      senderJID(instance.RemoteJID),
+		ServerUrl:   env.Env.ServerURL,
+	}
+	if env.Env.WebhookSendApikey {
+		result.Apikey = env.Env.ApiKey
+	}
+
+	if profile == ProfileEvolutionV1 {
+		result.DateTime = envelope.DateTime.Local().Format(time.RFC3339)
+	} else {
+		result.DateTime = envelope.DateTime.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+
+	return result
+}
+
+// profileData converts the message data to the shape of the Evolution version, other events are sent as they are
+func profileData(envelope wookEnvelope, profile string) any {
+	switch data := envelope.Data.(type) {
+	case *WookMessageData:
+		if profile == ProfileEvolutionV1 {
+			return &evolutionV1Message{
+				Key:              data.Key,
+				PushName:         data.PushName,
+				Message:          data.Message,
+				MessageType:      data.MessageType,
+				MessageTimestamp: data.MessageTimestamp,
+				Owner:            envelope.Instance,
+				Source:           data.Source,
+			}
+		}
+
+		return &evolutionV2Message{
+			Key:              data.Key,
+			PushName:         data.PushName,
+			Status:           data.Status,
+			Message:          data.Message,
+			ContextInfo:      data.ContextInfo,
+			MessageType:      data.MessageType,
+			MessageTimestamp: data.MessageTimestamp,
+			InstanceId:       data.InstanceId,
+			Source:           data.Source,
+		}
+	case *WookMessageUpdateData:
+		if profile == ProfileEvolutionV1 {
+			return &evolutionV1MessageUpdate{
+				RemoteJid:   data.RemoteJid,
+				FromMe:      data.FromMe,
+				Id:          data.KeyId,
+				Participant: data.Participant,
+				Status:      data.Status,
+				Datetime:    envelope.DateTime.UnixMilli(),
+				Owner:       envelope.Instance,
+			}
+		}
+	}
+
+	return envelope.Data
+}
+
+// senderJID is the instance number without the device, as Evolution sends it
+func senderJID(remoteJID string) string {
+	if remoteJID == "" {
+		return ""
+	}
+
+	jid, err := types.ParseJID(remoteJID)
+	if err != nil {
+		return remoteJID
+	}
+
+	return jid.ToNonAD().String()
+}
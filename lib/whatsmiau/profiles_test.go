@@ -0,0 +1,128 @@
+package whatsmiau
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// goldenEvents are rendered on every profile and locked by testdata/<profile>.golden
+func goldenEvents() []any {
+	dateTime := time.Date(2025, 1, 2, 15, 4, 5, 123000000, time.UTC)
+
+	return []any{
+		&WookEvent[WookMessageData]{
+			Event:    WookMessagesUpsert,
+			Instance: "my-instance",
+			DateTime: dateTime,
+			Data: &WookMessageData{
+				Key: &WookKey{
+					RemoteJid: "5511988887777@s.whatsapp.net",
+					Id:        "3EB0C431C26A1D7C2E0F",
+				},
+				PushName:         "Maria",
+				Status:           "DELIVERY_ACK",
+				Message:          &WookMessageRaw{Conversation: "hello"},
+				MessageType:      "conversation",
+				MessageTimestamp: int(dateTime.Unix()),
+				InstanceId:       "my-instance",
+				Source:           "android",
+			},
+		},
+		&WookEvent[WookMessageUpdateData]{
+			Event:    WookMessagesUpdate,
+			Instance: "my-instance",
+			DateTime: dateTime,
+			Data: &WookMessageUpdateData{
+				MessageId:   "3EB0C431C26A1D7C2E0F",
+				KeyId:       "3EB0C431C26A1D7C2E0F",
+				RemoteJid:   "120363025246125486@g.us",
+				FromMe:      true,
+				Participant: "5511988887777@s.whatsapp.net",
+				Status:      MessageStatusRead,
+				InstanceId:  "my-instance",
+			},
+		},
+		&WookEvent[WookConnectionUpdateData]{
+			Event:    WookConnectionUpdate,
+			Instance: "my-instance",
+			DateTime: dateTime,
+			Data:     &WookConnectionUpdateData{Status: "open"},
+		},
+	}
+}
+
+func TestRenderPayloadGolden(t *testing.T) {
+	oldEnv, oldLocal := env.Env, time.Local
+	t.Cleanup(func() {
+		env.Env, time.Local = oldEnv, oldLocal
+	})
+	env.Env.ServerURL = "https://whatsmiau.example.com"
+	env.Env.ApiKey = "secret-key"
+	env.Env.WebhookSendApikey = true
+	time.Local = time.FixedZone("-03", -3*60*60)
+
+	instance := &models.Instance{
+		ID:        "my-instance",
+		RemoteJID: "5511900001111:12@s.whatsapp.net",
+	}
+
+	for _, profile := range []string{ProfileEvolutionV2, ProfileEvolutionV1, ProfileCompact} {
+		t.Run(profile, func(t *testing.T) {
+			var rendered []any
+			for _, event := range goldenEvents() {
+				rendered = append(rendered, renderPayload(event, profile, instance, "https://receiver.example.com/webhook"))
+			}
+
+			got, err := json.MarshalIndent(rendered, "", "  ")
+			if err != nil {
+				t.Fatalf("failed to marshal payloads: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", profile+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("failed to update %s: %v", path, err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", path, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("payloads differ from %s (run go test -update to accept them):\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestSenderJID(t *testing.T) {
+	tests := []struct {
+		remoteJID string
+		want      string
+	}{
+		{remoteJID: "", want: ""},
+		{remoteJID: "5511900001111@s.whatsapp.net", want: "5511900001111@s.whatsapp.net"},
+		{remoteJID: "5511900001111:12@s.whatsapp.net", want: "5511900001111@s.whatsapp.net"},
+		{remoteJID: "5511900001111.0:12@s.whatsapp.net", want: "5511900001111@s.whatsapp.net"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remoteJID, func(t *testing.T) {
+			if got := senderJID(tt.remoteJID); got != tt.want {
+				t.Errorf("senderJID(%q) = %q, want %q", tt.remoteJID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package whatsmiau
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const maxReplyDelay = 10 * time.Second
+
+// WebhookReply is the optional body a webhook can answer a messages.upsert with, when the instance
+// has webhookReplies enabled. The actions run in order on behalf of the instance.
+//
+//	{"actions": [{"type": "presence", "presence": "composing", "delay": 1500}, {"type": "sendText", "text": "hi"}]}
+type WebhookReply struct {
+	Actions []WebhookReplyAction `json:"actions" validate:"max=10,dive"`
+}
+
+type WebhookReplyAction struct {
+	Type      string `json:"type" validate:"required,oneof=sendText sendMedia sendReaction markRead presence"`
+	Number    string `json:"number,omitempty"`                           // defaults to the chat of the event
+	MessageID string `json:"messageId,omitempty"`                        // sendReaction and markRead, defaults to the message of the event
+	Delay     int    `json:"delay,omitempty" validate:"min=0,max=10000"` // ms to wait before the action
+
+	Text      string `json:"text,omitempty" validate:"required_if=Type sendText,max=4096"`
+	MediaType string `json:"mediatype,omitempty" validate:"required_if=Type sendMedia,omitempty,oneof=image video audio document"`
+	Media     string `json:"media,omitempty" validate:"required_if=Type sendMedia,omitempty,url"`
+	Caption   string `json:"caption,omitempty"`
+	FileName  string `json:"fileName,omitempty"`
+	Mimetype  string `json:"mimetype,omitempty"`
+	Reaction  string `json:"reaction,omitempty" validate:"required_if=Type sendReaction"`
+	Presence  string `json:"presence,omitempty" validate:"required_if=Type presence,omitempty,oneof=composing recording paused"`
+}
+
+// replyTo returns the message reply actions refer to, only received messages of instances with webhookReplies accept them
+func replyTo(body any, instance *models.Instance) *models.WebhookReplyTo {
+	if instance.WebhookReplies == nil || !*instance.WebhookReplies {
+		return nil
+	}
+
+	evt, ok := body.(*WookEvent[WookMessageData])
+	if !ok || evt.Event != WookMessagesUpsert || evt.Data == nil || evt.Data.Key == nil || evt.Data.Key.FromMe {
+		return nil
+	}
+
+	return &models.WebhookReplyTo{
+		Chat:        evt.Data.Key.RemoteJid,
+		MessageID:   evt.Data.Key.Id,
+		Participant: evt.Data.Key.Participant,
+	}
+}
+
+// webhookResponse is the successful response of a delivery that accepts reply actions
+type webhookResponse struct {
+	contentType string
+	body        []byte
+}
+
+// startReplyActions runs the reply actions in their own goroutine, at most WEBHOOK_REPLY_WORKERS at the
+// same time, so their delays and sends never hold a delivery worker
+func (s *Whatsmiau) startReplyActions(delivery *models.WebhookDelivery, response *webhookResponse) {
+	go func() {
+		s.replySemaphore <- struct{}{}
+		defer func() { <-s.replySemaphore }()
+
+		s.runReplyActions(delivery, response.contentType, response.body)
+	}()
+}
+
+// runReplyActions executes the actions returned by the webhook
+func (s *Whatsmiau) runReplyActions(delivery *models.WebhookDelivery, contentType string, body []byte) {
+	logger := zap.L().With(zap.String("instance", delivery.InstanceID), zap.String("delivery", delivery.ID))
+
+	reply, err := parseWebhookReply(contentType, body)
+	if err != nil {
+		logger.Warn("invalid webhook reply, ignoring actions", zap.Error(err))
+		return
+	}
+	if reply == nil {
+		return
+	}
+
+	for i := range reply.Actions {
+		action := &reply.Actions[i]
+		if action.Delay > 0 {
+			time.Sleep(min(time.Duration(action.Delay)*time.Millisecond, maxReplyDelay))
+		}
+
+		if err := s.runReplyAction(delivery.InstanceID, delivery.Reply, action); err != nil {
+			logger.Error("failed to run webhook reply action", zap.String("type", action.Type), zap.Error(err))
+			return
+		}
+	}
+}
+
+// parseWebhookReply reads and validates the reply of a webhook. Bodies that are not a JSON object return no
+// reply, so receivers answering "ok" keep working.
+func parseWebhookReply(contentType string, body []byte) (*WebhookReply, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' || !strings.Contains(contentType, "json") {
+		return nil, nil
+	}
+
+	if int64(len(body)) > env.Env.WebhookReplyMaxBytes {
+		return nil, fmt.Errorf("reply larger than %d bytes", env.Env.WebhookReplyMaxBytes)
+	}
+
+	var reply WebhookReply
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return nil, err
+	}
+
+	if err := validator.New().Struct(&reply); err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
+// replyTarget returns the chat and message of the action, defaulting to the ones of the event
+func replyTarget(replyTo *models.WebhookReplyTo, action *WebhookReplyAction) (types.JID, string, error) {
+	chat := replyTo.Chat
+	if action.Number != "" {
+		chat = action.Number
+		if !strings.Contains(chat, "@") {
+			chat += "@" + types.DefaultUserServer
+		}
+	}
+
+	jid, err := types.ParseJID(chat)
+	if err != nil {
+		return types.EmptyJID, "", fmt.Errorf("invalid number: %w", err)
+	}
+
+	messageID := replyTo.MessageID
+	if action.MessageID != "" {
+		messageID = action.MessageID
+	}
+
+	return jid, messageID, nil
+}
+
+func (s *Whatsmiau) runReplyAction(instanceID string, replyTo *models.WebhookReplyTo, action *WebhookReplyAction) error {
+	jid, messageID, err := replyTarget(replyTo, action)
+	if err != nil {
+		return err
+	}
+
+	ctx, c := context.WithTimeout(context.Background(), time.Minute)
+	defer c()
+
+	switch action.Type {
+	case "sendText":
+		_, err = s.SendText(ctx, &SendText{
+			Text:       action.Text,
+			InstanceID: instanceID,
+			RemoteJID:  &jid,
+		})
+	case "sendMedia":
+		err = s.sendReplyMedia(ctx, instanceID, &jid, action)
+	case "sendReaction":
+		_, err = s.SendReaction(ctx, &SendReactionRequest{
+			InstanceID: instanceID,
+			Reaction:   action.Reaction,
+			RemoteJID:  &jid,
+			MessageID:  messageID,
+		})
+	case "markRead":
+		request := &ReadMessageRequest{
+			MessageIDs: []string{messageID},
+			InstanceID: instanceID,
+			RemoteJID:  &jid,
+		}
+		if replyTo.Participant != "" {
+			if participant, err := types.ParseJID(replyTo.Participant); err == nil {
+				request.Sender = &participant
+			}
+		}
+		err = s.ReadMessage(request)
+	case "presence":
+		request := &ChatPresenceRequest{
+			InstanceID: instanceID,
+			RemoteJID:  &jid,
+			Presence:   types.ChatPresenceComposing,
+		}
+		switch action.Presence {
+		case "recording":
+			request.Media = types.ChatPresenceMediaAudio
+		case "paused":
+			request.Presence = types.ChatPresencePaused
+		}
+		err = s.ChatPresence(request)
+	default:
+		err = errors.New("unknown action")
+	}
+
+	return err
+}
+
+func (s *Whatsmiau) sendReplyMedia(ctx context.Context, instanceID string, jid *types.JID, action *WebhookReplyAction) error {
+	var err error
+	switch action.MediaType {
+	case "image":
+		_, err = s.SendImage(ctx, &SendImageRequest{
+			InstanceID: instanceID,
+			MediaURL:   action.Media,
+			Caption:    action.Caption,
+			RemoteJID:  jid,
+			Mimetype:   action.Mimetype,
+		})
+	case "video":
+		_, err = s.SendVideo(ctx, &SendVideoRequest{
+			InstanceID: instanceID,
+			MediaURL:   action.Media,
+			Caption:    action.Caption,
+			RemoteJID:  jid,
+			Mimetype:   action.Mimetype,
+		})
+	case "audio":
+		_, err = s.SendAudio(ctx, &SendAudioRequest{
+			AudioURL:   action.Media,
+			InstanceID: instanceID,
+			RemoteJID:  jid,
+		})
+	default:
+		_, err = s.SendDocument(ctx, &SendDocumentRequest{
+			InstanceID: instanceID,
+			MediaURL:   action.Media,
+			Caption:    action.Caption,
+			FileName:   action.FileName,
+			RemoteJID:  jid,
+			Mimetype:   action.Mimetype,
+		})
+	}
+
+	return err
+}
@@ -0,0 +1,239 @@
+package whatsmiau
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+func TestParseWebhookReply(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookReplyMaxBytes = 256
+
+	tenActions := `{"actions":[` + strings.TrimSuffix(strings.Repeat(`{"type":"markRead"},`, 10), ",") + `]}`
+	elevenActions := `{"actions":[` + strings.TrimSuffix(strings.Repeat(`{"type":"markRead"},`, 11), ",") + `]}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantActions int
+		wantNil     bool
+		wantErr     bool
+	}{
+		{name: "text", contentType: "application/json", body: `{"actions":[{"type":"sendText","text":"hi"}]}`, wantActions: 1},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: ` {"actions":[{"type":"sendText","text":"hi"}]} `, wantActions: 1},
+		{name: "plain text content type", contentType: "text/plain", body: `{"actions":[{"type":"sendText","text":"hi"}]}`, wantNil: true},
+		{name: "missing content type", body: `{"actions":[{"type":"sendText","text":"hi"}]}`, wantNil: true},
+		{name: "plain ok", contentType: "application/json", body: `ok`, wantNil: true},
+		{name: "json array", contentType: "application/json", body: `[{"type":"sendText","text":"hi"}]`, wantNil: true},
+		{name: "empty body", contentType: "application/json", body: ``, wantNil: true},
+		{name: "object without actions", contentType: "application/json", body: `{"received":true}`, wantActions: 0},
+		{name: "invalid json", contentType: "application/json", body: `{"actions":[`, wantErr: true},
+		{name: "too large", contentType: "application/json", body: `{"actions":[{"type":"sendText","text":"` + strings.Repeat("a", 256) + `"}]}`, wantErr: true},
+		{name: "ten actions", contentType: "application/json", body: tenActions, wantActions: 10},
+		{name: "more than ten actions", contentType: "application/json", body: elevenActions, wantErr: true},
+		{name: "unknown type", contentType: "application/json", body: `{"actions":[{"type":"deleteChat"}]}`, wantErr: true},
+		{name: "missing type", contentType: "application/json", body: `{"actions":[{"text":"hi"}]}`, wantErr: true},
+		{name: "sendText without text", contentType: "application/json", body: `{"actions":[{"type":"sendText"}]}`, wantErr: true},
+		{name: "text is only required on sendText", contentType: "application/json", body: `{"actions":[{"type":"sendReaction","reaction":"👍"}]}`, wantActions: 1},
+		{name: "sendMedia without media", contentType: "application/json", body: `{"actions":[{"type":"sendMedia","mediatype":"image"}]}`, wantErr: true},
+		{name: "sendMedia without mediatype", contentType: "application/json", body: `{"actions":[{"type":"sendMedia","media":"https://example.com/a.png"}]}`, wantErr: true},
+		{name: "sendMedia with invalid url", contentType: "application/json", body: `{"actions":[{"type":"sendMedia","mediatype":"image","media":"a.png"}]}`, wantErr: true},
+		{name: "sendMedia", contentType: "application/json", body: `{"actions":[{"type":"sendMedia","mediatype":"image","media":"https://example.com/a.png"}]}`, wantActions: 1},
+		{name: "sendReaction without reaction", contentType: "application/json", body: `{"actions":[{"type":"sendReaction"}]}`, wantErr: true},
+		{name: "presence without presence", contentType: "application/json", body: `{"actions":[{"type":"presence"}]}`, wantErr: true},
+		{name: "invalid presence", contentType: "application/json", body: `{"actions":[{"type":"presence","presence":"online"}]}`, wantErr: true},
+		{name: "delay over the limit", contentType: "application/json", body: `{"actions":[{"type":"markRead","delay":10001}]}`, wantErr: true},
+		{name: "negative delay", contentType: "application/json", body: `{"actions":[{"type":"markRead","delay":-1}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := parseWebhookReply(tt.contentType, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWebhookReply() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (reply == nil) != tt.wantNil {
+				t.Fatalf("parseWebhookReply() = %+v, wantNil %t", reply, tt.wantNil)
+			}
+			if reply != nil && len(reply.Actions) != tt.wantActions {
+				t.Errorf("parseWebhookReply() has %d actions, want %d", len(reply.Actions), tt.wantActions)
+			}
+		})
+	}
+}
+
+func TestReplyTarget(t *testing.T) {
+	replyTo := &models.WebhookReplyTo{
+		Chat:        "120363025246125486@g.us",
+		MessageID:   "3EB0C431C26A1D7C2E0F",
+		Participant: "5511988887777@s.whatsapp.net",
+	}
+
+	tests := []struct {
+		name          string
+		action        WebhookReplyAction
+		wantChat      string
+		wantMessageID string
+		wantErr       bool
+	}{
+		{name: "event chat and message", action: WebhookReplyAction{Type: "sendReaction"}, wantChat: "120363025246125486@g.us", wantMessageID: "3EB0C431C26A1D7C2E0F"},
+		{name: "number", action: WebhookReplyAction{Type: "sendText", Number: "5511977776666"}, wantChat: "5511977776666@s.whatsapp.net", wantMessageID: "3EB0C431C26A1D7C2E0F"},
+		{name: "jid", action: WebhookReplyAction{Type: "sendText", Number: "120363000000000000@g.us"}, wantChat: "120363000000000000@g.us", wantMessageID: "3EB0C431C26A1D7C2E0F"},
+		{name: "message id", action: WebhookReplyAction{Type: "markRead", MessageID: "ABC"}, wantChat: "120363025246125486@g.us", wantMessageID: "ABC"},
+		{name: "invalid device", action: WebhookReplyAction{Type: "sendText", Number: "5511977776666:x"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jid, messageID, err := replyTarget(replyTo, &tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("replyTarget() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if jid.String() != tt.wantChat || messageID != tt.wantMessageID {
+				t.Errorf("replyTarget() = %s, %q, want %s, %q", jid, messageID, tt.wantChat, tt.wantMessageID)
+			}
+		})
+	}
+}
+
+func TestReplyTo(t *testing.T) {
+	enabled, disabled := true, false
+	message := func(event Wook, fromMe bool) *WookEvent[WookMessageData] {
+		return &WookEvent[WookMessageData]{
+			Event: event,
+			Data: &WookMessageData{Key: &WookKey{
+				RemoteJid:   "120363025246125486@g.us",
+				FromMe:      fromMe,
+				Id:          "3EB0C431C26A1D7C2E0F",
+				Participant: "5511988887777@s.whatsapp.net",
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		replies *bool
+		body    any
+		want    bool
+	}{
+		{name: "received message", replies: &enabled, body: message(WookMessagesUpsert, false), want: true},
+		{name: "replies not set", body: message(WookMessagesUpsert, false), want: false},
+		{name: "replies disabled", replies: &disabled, body: message(WookMessagesUpsert, false), want: false},
+		{name: "sent message", replies: &enabled, body: message(WookMessagesUpsert, true), want: false},
+		{name: "other event", replies: &enabled, body: message(WookSendMessage, false), want: false},
+		{name: "connection update", replies: &enabled, body: &WookEvent[WookConnectionUpdateData]{Event: WookConnectionUpdate}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replyTo(tt.body, &models.Instance{WebhookReplies: tt.replies})
+			if (got != nil) != tt.want {
+				t.Fatalf("replyTo() = %+v, want reply %t", got, tt.want)
+			}
+			if got != nil && (got.Chat != "120363025246125486@g.us" || got.MessageID != "3EB0C431C26A1D7C2E0F" || got.Participant != "5511988887777@s.whatsapp.net") {
+				t.Errorf("replyTo() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSendWebhookReplyResponse(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookRetrySchedule = nil
+	env.Env.WebhookBreakerThreshold = 0
+	env.Env.WebhookReplyMaxBytes = 64
+
+	reply := `{"actions":[{"type":"sendText","text":"hi"}]}`
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		withReply    bool
+		wantErr      bool
+		wantResponse bool
+	}{
+		{name: "success with reply", status: http.StatusOK, body: reply, withReply: true, wantResponse: true},
+		{name: "instance without replies", status: http.StatusOK, body: reply, wantResponse: false},
+		{name: "other 2xx", status: http.StatusAccepted, body: reply, withReply: true, wantResponse: true},
+		{name: "client error", status: http.StatusBadRequest, body: reply, withReply: true, wantErr: true},
+		{name: "server error", status: http.StatusInternalServerError, body: reply, withReply: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			s := &Whatsmiau{httpClient: server.Client(), breakers: newCircuitBreakers()}
+			delivery := &models.WebhookDelivery{ID: "delivery-1", URL: server.URL, Payload: []byte(`{}`)}
+			if tt.withReply {
+				delivery.Reply = &models.WebhookReplyTo{Chat: "5511988887777@s.whatsapp.net", MessageID: "A"}
+			}
+
+			response, err := s.sendWebhookWithRetry(delivery)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendWebhookWithRetry() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if (response != nil) != tt.wantResponse {
+				t.Fatalf("sendWebhookWithRetry() response = %+v, want response %t", response, tt.wantResponse)
+			}
+			if response != nil && (string(response.body) != tt.body || response.contentType != "application/json") {
+				t.Errorf("response = %q (%s), want %q (application/json)", response.body, response.contentType, tt.body)
+			}
+		})
+	}
+}
+
+func TestSendWebhookReplyResponseLimit(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.WebhookRetrySchedule = nil
+	env.Env.WebhookBreakerThreshold = 0
+	env.Env.WebhookReplyMaxBytes = 64
+
+	body := `{"actions":[{"type":"sendText","text":"` + strings.Repeat("a", 1024) + `"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	s := &Whatsmiau{httpClient: server.Client(), breakers: newCircuitBreakers()}
+	delivery := &models.WebhookDelivery{
+		ID:      "delivery-1",
+		URL:     server.URL,
+		Payload: []byte(`{}`),
+		Reply:   &models.WebhookReplyTo{Chat: "5511988887777@s.whatsapp.net", MessageID: "A"},
+	}
+
+	response, err := s.sendWebhookWithRetry(delivery)
+	if err != nil {
+		t.Fatalf("sendWebhookWithRetry() error = %v", err)
+	}
+	// only the limit plus one byte is read, enough to reject the reply without buffering it
+	if len(response.body) != 65 {
+		t.Fatalf("read %d bytes, want 65", len(response.body))
+	}
+	if reply, err := parseWebhookReply(response.contentType, response.body); err == nil || reply != nil {
+		t.Errorf("parseWebhookReply() = %+v, %v, want the size error", reply, err)
+	}
+}
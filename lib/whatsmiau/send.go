@@ -55,14 +55,18 @@ func (s *Whatsmiau) SendText(ctx context.Context, data *SendText) (*SendTextResp
 		}
 	}
 
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		Conversation:        &data.Text,
 		ExtendedTextMessage: extendedMessage,
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendTextResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -139,13 +143,17 @@ func (s *Whatsmiau) SendAudio(ctx context.Context, data *SendAudioRequest) (*Sen
 		ViewOnce:      proto.Bool(data.ViewOnce),
 	}
 
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		AudioMessage: &audio,
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendAudioResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -199,13 +207,17 @@ func (s *Whatsmiau) SendDocument(ctx context.Context, data *SendDocumentRequest)
 		Caption:       proto.String(data.Caption),
 	}
 
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		DocumentMessage: &doc,
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendDocumentResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -278,13 +290,17 @@ func (s *Whatsmiau) SendImage(ctx context.Context, data *SendImageRequest) (*Sen
 		ViewOnce:      proto.Bool(data.ViewOnce),
 	}
 
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		ImageMessage: &doc,
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendImageResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -330,9 +346,12 @@ func (s *Whatsmiau) SendReaction(ctx context.Context, data *SendReactionRequest)
 	doc := client.BuildReaction(*data.RemoteJID, *sender, data.MessageID, data.Reaction)
 	res, err := client.SendMessage(ctx, *data.RemoteJID, doc)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, doc)
+
 	return &SendReactionResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -395,13 +414,17 @@ func (s *Whatsmiau) SendVideo(ctx context.Context, data *SendVideoRequest) (*Sen
 		ViewOnce:      proto.Bool(data.ViewOnce),
 	}
 
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		VideoMessage: &video,
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendVideoResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -474,13 +497,17 @@ func (s *Whatsmiau) SendMissedCall(ctx context.Context, data *SendMissedCallRequ
 	}
 
 	// Enviar a mensagem
-	res, err := client.SendMessage(ctx, *data.RemoteJID, &waE2E.Message{
+	message := &waE2E.Message{
 		CallLogMesssage: callLogMsg, // Nota: campo tem typo "Messsage" no protobuf original
-	})
+	}
+	res, err := client.SendMessage(ctx, *data.RemoteJID, message)
 	if err != nil {
+		go s.handleSendError(data.InstanceID, *data.RemoteJID, res)
 		return nil, err
 	}
 
+	go s.handleSentMessage(data.InstanceID, *data.RemoteJID, res, message)
+
 	return &SendMissedCallResponse{
 		ID:        res.ID,
 		CreatedAt: res.Timestamp,
@@ -0,0 +1,51 @@
+package whatsmiau
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderWebhookSignature = "X-Whatsmiau-Signature"
+	HeaderWebhookTimestamp = "X-Whatsmiau-Timestamp"
+	HeaderWebhookDelivery  = "X-Whatsmiau-Delivery"
+)
+
+// GenerateWebhookSecret returns a random secret used to sign the webhook deliveries of an instance
+func GenerateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhook computes the signature sent on X-Whatsmiau-Signature.
+// Receivers must recompute HMAC-SHA256(secret, "<timestamp>.<body>") and compare both values.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// setWebhookHeaders adds the delivery id, timestamp and signature headers to a webhook request
+func setWebhookHeaders(req *http.Request, deliveryID, secret string, body []byte) {
+	timestamp := time.Now().Unix()
+
+	req.Header.Set(HeaderWebhookDelivery, deliveryID)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	if secret != "" {
+		req.Header.Set(HeaderWebhookSignature, SignWebhook(secret, timestamp, body))
+	} else {
+		req.Header.Del(HeaderWebhookSignature)
+	}
+}
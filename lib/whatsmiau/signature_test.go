@@ -0,0 +1,116 @@
+package whatsmiau
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"event":"messages.upsert"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      string
+	}{
+		{
+			name:      "body",
+			secret:    "my-secret",
+			timestamp: 1735830245,
+			body:      body,
+			want:      "sha256=54001aec2384e52dddf518f868f260348a9242e3ba87362e121c89f5d5270018",
+		},
+		{
+			name:      "empty body",
+			secret:    "my-secret",
+			timestamp: 1735830245,
+			want:      "sha256=0673e33d87e8a79f339d152f2c4db99770da5ea0d12f3e93fad818f07030941d",
+		},
+		{
+			name:      "other secret",
+			secret:    "other-secret",
+			timestamp: 1735830245,
+			body:      body,
+			want:      "sha256=cc9a490000fba893f4589a5ec7b66198b29587524f2541c1c4ba5d772fea9de2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SignWebhook(tt.secret, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("SignWebhook() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetWebhookHeaders(t *testing.T) {
+	body := []byte(`{"event":"messages.upsert"}`)
+
+	tests := []struct {
+		name          string
+		secret        string
+		wantSignature bool
+	}{
+		{name: "signed", secret: "my-secret", wantSignature: true},
+		{name: "without secret", secret: "", wantSignature: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://receiver.example.com/webhook", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			before := time.Now().Unix()
+			setWebhookHeaders(req, "delivery-1", tt.secret, body)
+
+			if got := req.Header.Get(HeaderWebhookDelivery); got != "delivery-1" {
+				t.Errorf("%s = %q, want delivery-1", HeaderWebhookDelivery, got)
+			}
+
+			timestamp, err := strconv.ParseInt(req.Header.Get(HeaderWebhookTimestamp), 10, 64)
+			if err != nil {
+				t.Fatalf("invalid %s: %v", HeaderWebhookTimestamp, err)
+			}
+			if timestamp < before || timestamp > time.Now().Unix() {
+				t.Errorf("%s = %d, want the current time", HeaderWebhookTimestamp, timestamp)
+			}
+
+			signature := req.Header.Get(HeaderWebhookSignature)
+			if !tt.wantSignature {
+				if signature != "" {
+					t.Errorf("%s = %q, want none", HeaderWebhookSignature, signature)
+				}
+				return
+			}
+			// receivers verify the signature with the timestamp header
+			if want := SignWebhook(tt.secret, timestamp, body); signature != want {
+				t.Errorf("%s = %q, want %q", HeaderWebhookSignature, signature, want)
+			}
+		})
+	}
+}
+
+func TestGenerateWebhookSecret(t *testing.T) {
+	first, err := GenerateWebhookSecret()
+	if err != nil {
+		t.Fatalf("GenerateWebhookSecret() error = %v", err)
+	}
+	second, err := GenerateWebhookSecret()
+	if err != nil {
+		t.Fatalf("GenerateWebhookSecret() error = %v", err)
+	}
+
+	if len(first) != 64 {
+		t.Errorf("secret length = %d, want 64", len(first))
+	}
+	if first == second {
+		t.Errorf("secrets should differ, both are %q", first)
+	}
+}
@@ -0,0 +1,99 @@
+package whatsmiau
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/lib/sink/amqp"
+	"github.com/verbeux-ai/whatsmiau/lib/sink/nats"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const (
+	SinkWebhook = "webhook"
+	SinkNats    = "nats"
+	SinkAmqp    = "amqp"
+)
+
+// loadSinks connects to the configured brokers
+func loadSinks() map[string]interfaces.EventSink {
+	sinks := make(map[string]interfaces.EventSink)
+
+	if env.Env.NatsURL != "" {
+		sink, err := nats.New(env.Env.NatsURL, env.Env.NatsStream, env.Env.EventSinkPrefix)
+		if err != nil {
+			zap.L().Panic("failed to connect to NATS", zap.Error(err))
+		}
+		sinks[SinkNats] = sink
+	}
+
+	if env.Env.AmqpURL != "" {
+		sink, err := amqp.New(env.Env.AmqpURL, env.Env.AmqpExchange)
+		if err != nil {
+			zap.L().Panic("failed to connect to AMQP", zap.Error(err))
+		}
+		sinks[SinkAmqp] = sink
+	}
+
+	return sinks
+}
+
+func instanceSink(instance *models.Instance) string {
+	if instance.Sink != "" {
+		return instance.Sink
+	}
+	if env.Env.EventSink != "" {
+		return env.Env.EventSink
+	}
+
+	return SinkWebhook
+}
+
+// SinkSubject returns the NATS subject / AMQP routing key of the event: <prefix>.<instance>.<event>
+// (e.g. whatsmiau.my-instance.messages.upsert), so consumers can subscribe by instance or event with wildcards
+func SinkSubject(instanceID, event string) string {
+	instanceID = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_", "#", "_").Replace(instanceID)
+
+	return env.Env.EventSinkPrefix + "." + instanceID + "." + event
+}
+
+// publishWithRetry sends the delivery to its broker following the same retry schedule of webhooks
+func (s *Whatsmiau) publishWithRetry(delivery *models.WebhookDelivery) error {
+	sink, ok := s.sinks[delivery.Sink]
+	if !ok {
+		return fmt.Errorf("event sink %q is not configured", delivery.Sink)
+	}
+
+	subject := SinkSubject(delivery.InstanceID, delivery.Event)
+	retryDelays := env.Env.WebhookRetrySchedule
+
+	var lastErr error
+	for attempt := 0; attempt <= len(retryDelays); attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelays[attempt-1])
+		}
+		delivery.Attempts++
+
+		ctx, c := context.WithTimeout(context.Background(), 10*time.Second)
+		start := time.Now()
+		lastErr = sink.Publish(ctx, subject, delivery)
+		delivery.LatencyMs = time.Since(start).Milliseconds()
+		c()
+		if lastErr == nil {
+			return nil
+		}
+
+		zap.L().Warn("failed to publish event",
+			zap.String("sink", delivery.Sink),
+			zap.String("subject", subject),
+			zap.Int("attempt", attempt+1),
+			zap.Error(lastErr))
+	}
+
+	return fmt.Errorf("failed to publish event after %d attempts: %w", len(retryDelays)+1, lastErr)
+}
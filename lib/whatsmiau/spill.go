@@ -0,0 +1,218 @@
+package whatsmiau
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.uber.org/zap"
+)
+
+const spillCurrentFile = "current.jsonl"
+
+// spillQueue keeps on disk the events that did not fit on the emitter buffer (EMITTER_FULL_POLICY=spill).
+// The events are appended as json lines and moved to the outbox once the buffer has room again.
+// While anything is spilled the newer events are spilled too, so they never get ahead of the older ones.
+type spillQueue struct {
+	dir    string
+	mu     sync.Mutex
+	active bool // there are spilled events not drained yet
+}
+
+func newSpillQueue(dir string) *spillQueue {
+	q := &spillQueue{dir: dir}
+
+	// events spilled before a restart are drained first
+	if _, err := os.Stat(filepath.Join(dir, spillCurrentFile)); err == nil {
+		q.active = true
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "spill-*.jsonl")); len(files) > 0 {
+		q.active = true
+	}
+
+	return q
+}
+
+// WriteIfActive spills the delivery only when older events are still spilled
+func (q *spillQueue) WriteIfActive(delivery *models.WebhookDelivery) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.active {
+		return false
+	}
+
+	q.write(delivery)
+	return true
+}
+
+func (q *spillQueue) Write(delivery *models.WebhookDelivery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.active = true
+	q.write(delivery)
+}
+
+func (q *spillQueue) write(delivery *models.WebhookDelivery) {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		zap.L().Error("failed to marshal spilled event", zap.Error(err))
+		return
+	}
+
+	if err := os.MkdirAll(q.dir, 0o755); err != nil {
+		zap.L().Error("failed to create spill dir", zap.String("dir", q.dir), zap.Error(err))
+		return
+	}
+
+	file, err := os.OpenFile(filepath.Join(q.dir, spillCurrentFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		zap.L().Error("failed to open spill file", zap.Error(err))
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		zap.L().Error("failed to spill event", zap.String("delivery", delivery.ID), zap.Error(err))
+	}
+}
+
+// rotate closes the current spill file, returning every file ready to be drained (oldest first).
+// When nothing is left the queue is deactivated and the events go back to the emitter buffer.
+func (q *spillQueue) rotate() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	current := filepath.Join(q.dir, spillCurrentFile)
+	if _, err := os.Stat(current); err == nil {
+		rotated := filepath.Join(q.dir, fmt.Sprintf("spill-%d.jsonl", time.Now().UnixNano()))
+		if err := os.Rename(current, rotated); err != nil {
+			zap.L().Error("failed to rotate spill file", zap.Error(err))
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(q.dir, "spill-*.jsonl"))
+	if err != nil {
+		return nil
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		q.active = false
+	}
+
+	return files
+}
+
+// startSpillDrainer moves the spilled events back through the emitter when its buffer is below half of
+// its capacity. The events enqueued while draining are spilled behind them, keeping the original order.
+func (s *Whatsmiau) startSpillDrainer() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if len(s.emitter) > cap(s.emitter)/2 {
+			continue
+		}
+
+	drain:
+		for files := s.spill.rotate(); len(files) > 0; files = s.spill.rotate() {
+			for _, file := range files {
+				// a broken file is retried on the next tick, still ahead of the newer events
+				if !s.drainSpillFile(file) {
+					break drain
+				}
+			}
+		}
+	}
+}
+
+// drainSpillFile hands the events of the file to the emitter. When it stops partway the offset of the events
+// already handed over is kept next to the file, so the next attempt resumes after them instead of emitting them again.
+func (s *Whatsmiau) drainSpillFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		zap.L().Error("failed to open spill file", zap.String("file", path), zap.Error(err))
+		return false
+	}
+	defer file.Close()
+
+	offset := readSpillOffset(path)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		zap.L().Error("failed to seek spill file", zap.String("file", path), zap.Error(err))
+		return false
+	}
+
+	total := 0
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && (err == nil || errors.Is(err, io.EOF)) {
+			var delivery models.WebhookDelivery
+			if jsonErr := json.Unmarshal(line, &delivery); jsonErr != nil {
+				zap.L().Error("failed to decode spilled event", zap.String("file", path), zap.Error(jsonErr))
+			} else {
+				s.emitter <- &delivery
+				total++
+			}
+			offset += int64(len(line))
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			zap.L().Error("failed to read spill file", zap.String("file", path), zap.Error(err))
+			writeSpillOffset(path, offset)
+			return false
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		zap.L().Error("failed to remove spill file", zap.String("file", path), zap.Error(err))
+		writeSpillOffset(path, offset)
+		return false
+	}
+	if err := os.Remove(spillOffsetPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		zap.L().Error("failed to remove spill offset", zap.String("file", path), zap.Error(err))
+	}
+
+	zap.L().Info("spilled events moved back to the emitter", zap.String("file", path), zap.Int("total", total))
+	return true
+}
+
+func spillOffsetPath(path string) string {
+	return path + ".offset"
+}
+
+// readSpillOffset returns where a previous drain of the file stopped, 0 when it was never drained
+func readSpillOffset(path string) int64 {
+	data, err := os.ReadFile(spillOffsetPath(path))
+	if err != nil {
+		return 0
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 {
+		return 0
+	}
+
+	return offset
+}
+
+func writeSpillOffset(path string, offset int64) {
+	if err := os.WriteFile(spillOffsetPath(path), []byte(strconv.FormatInt(offset, 10)), 0o644); err != nil {
+		zap.L().Error("failed to save spill offset, drained events may be emitted again", zap.String("file", path), zap.Error(err))
+	}
+}
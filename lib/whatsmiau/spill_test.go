@@ -0,0 +1,96 @@
+package whatsmiau
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+func TestDrainSpillFile(t *testing.T) {
+	var lines [][]byte
+	for _, id := range []string{"delivery-1", "delivery-2", "delivery-3"} {
+		line, err := json.Marshal(&models.WebhookDelivery{ID: id, InstanceID: "instance-1"})
+		if err != nil {
+			t.Fatalf("failed to marshal delivery: %v", err)
+		}
+		lines = append(lines, append(line, '\n'))
+	}
+	firstLine := strconv.Itoa(len(lines[0]))
+	allLines := strconv.Itoa(len(lines[0]) + len(lines[1]) + len(lines[2]))
+
+	tests := []struct {
+		name    string
+		content string
+		offset  string // saved by a previous drain, empty when none
+		want    []string
+	}{
+		{name: "whole file", content: string(lines[0]) + string(lines[1]) + string(lines[2]), want: []string{"delivery-1", "delivery-2", "delivery-3"}},
+		{name: "resumes after the drained events", content: string(lines[0]) + string(lines[1]) + string(lines[2]), offset: firstLine, want: []string{"delivery-2", "delivery-3"}},
+		{name: "fully drained file is only removed", content: string(lines[0]) + string(lines[1]) + string(lines[2]), offset: allLines, want: nil},
+		{name: "invalid offset reads from the start", content: string(lines[0]), offset: "abc", want: []string{"delivery-1"}},
+		{name: "broken lines are skipped", content: string(lines[0]) + "{broken\n" + string(lines[1]), want: []string{"delivery-1", "delivery-2"}},
+		{name: "last line without newline", content: string(lines[0]) + string(lines[1][:len(lines[1])-1]), want: []string{"delivery-1", "delivery-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spill-1.jsonl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write spill file: %v", err)
+			}
+			if tt.offset != "" {
+				if err := os.WriteFile(spillOffsetPath(path), []byte(tt.offset), 0o644); err != nil {
+					t.Fatalf("failed to write spill offset: %v", err)
+				}
+			}
+
+			s := &Whatsmiau{emitter: make(chan *models.WebhookDelivery, 10)}
+			if !s.drainSpillFile(path) {
+				t.Fatal("drainSpillFile() = false, want true")
+			}
+			close(s.emitter)
+
+			var got []string
+			for delivery := range s.emitter {
+				got = append(got, delivery.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("emitted %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("emitted %v, want %v", got, tt.want)
+				}
+			}
+
+			for _, leftover := range []string{path, spillOffsetPath(path)} {
+				if _, err := os.Stat(leftover); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("%s was not removed", filepath.Base(leftover))
+				}
+			}
+		})
+	}
+}
+
+func TestSpillOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill-1.jsonl")
+
+	if got := readSpillOffset(path); got != 0 {
+		t.Fatalf("readSpillOffset() without offset = %d, want 0", got)
+	}
+
+	writeSpillOffset(path, 1234)
+	if got := readSpillOffset(path); got != 1234 {
+		t.Fatalf("readSpillOffset() = %d, want 1234", got)
+	}
+
+	// offsets are never mistaken for spill files
+	if files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "spill-*.jsonl")); len(files) != 0 {
+		t.Errorf("spill files = %v, want none", files)
+	}
+}
@@ -0,0 +1,161 @@
+package whatsmiau
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"golang.org/x/net/context"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendStatusRequest struct {
+	InstanceID      string `json:"instance_id"`
+	Type            string `json:"type"`    // text, image, video or audio
+	Content         string `json:"content"` // text, or URL/base64 data URI of the media
+	Caption         string `json:"caption"`
+	BackgroundColor string `json:"background_color"` // #RRGGBB, text only
+	Font            int    `json:"font"`             // ExtendedTextMessage font type, text only
+}
+
+type SendStatusResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Whatsmiau) SendStatus(ctx context.Context, data *SendStatusRequest) (*SendStatusResponse, error) {
+	client, ok := s.clients.Load(data.InstanceID)
+	if !ok {
+		return nil, whatsmeow.ErrClientIsNil
+	}
+
+	message, err := s.statusMessage(ctx, client, data)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.SendMessage(ctx, types.StatusBroadcastJID, message)
+	if err != nil {
+		go s.handleSendError(data.InstanceID, types.StatusBroadcastJID, res)
+		return nil, err
+	}
+
+	go s.handleSentMessage(data.InstanceID, types.StatusBroadcastJID, res, message)
+
+	return &SendStatusResponse{
+		ID:        res.ID,
+		CreatedAt: res.Timestamp,
+	}, nil
+}
+
+func (s *Whatsmiau) statusMessage(ctx context.Context, client *whatsmeow.Client, data *SendStatusRequest) (*waE2E.Message, error) {
+	if data.Type == "text" {
+		text := &waE2E.ExtendedTextMessage{
+			Text:     proto.String(data.Content),
+			TextArgb: proto.Uint32(0xFFFFFFFF),
+			Font:     waE2E.ExtendedTextMessage_FontType(data.Font).Enum(),
+		}
+
+		background, err := parseArgb(data.BackgroundColor)
+		if err != nil {
+			return nil, err
+		}
+		text.BackgroundArgb = proto.Uint32(background)
+
+		return &waE2E.Message{ExtendedTextMessage: text}, nil
+	}
+
+	media, err := s.loadMedia(ctx, data.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.Type {
+	case "image":
+		uploaded, err := client.Upload(ctx, media, whatsmeow.MediaImage)
+		if err != nil {
+			return nil, err
+		}
+
+		mimetype, err := extractMimetype(media, uploaded.URL)
+		if err != nil {
+			mimetype = "image/jpeg"
+		}
+
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL:           proto.String(uploaded.URL),
+			Mimetype:      proto.String(mimetype),
+			Caption:       proto.String(data.Caption),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			DirectPath:    proto.String(uploaded.DirectPath),
+		}}, nil
+	case "video":
+		uploaded, err := client.Upload(ctx, media, whatsmeow.MediaVideo)
+		if err != nil {
+			return nil, err
+		}
+
+		mimetype, err := extractMimetype(media, uploaded.URL)
+		if err != nil {
+			mimetype = "video/mp4"
+		}
+
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(uploaded.URL),
+			Mimetype:      proto.String(mimetype),
+			Caption:       proto.String(data.Caption),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			DirectPath:    proto.String(uploaded.DirectPath),
+		}}, nil
+	case "audio":
+		audio, waveForm, secs, err := convertAudio(media, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		uploaded, err := client.Upload(ctx, audio, whatsmeow.MediaAudio)
+		if err != nil {
+			return nil, err
+		}
+
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			URL:           proto.String(uploaded.URL),
+			Mimetype:      proto.String("audio/ogg; codecs=opus"),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			Seconds:       proto.Uint32(uint32(secs)),
+			PTT:           proto.Bool(true),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			DirectPath:    proto.String(uploaded.DirectPath),
+			Waveform:      waveForm,
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("invalid status type: %s", data.Type)
+}
+
+// parseArgb converts a #RRGGBB color to the opaque ARGB used by text statuses, defaulting to black
+func parseArgb(color string) (uint32, error) {
+	color = strings.TrimPrefix(color, "#")
+	if color == "" {
+		return 0xFF000000, nil
+	}
+
+	rgb, err := strconv.ParseUint(color, 16, 32)
+	if err != nil || len(color) != 6 {
+		return 0, fmt.Errorf("invalid color: %s", color)
+	}
+
+	return 0xFF000000 | uint32(rgb), nil
+}
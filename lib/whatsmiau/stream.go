@@ -0,0 +1,115 @@
+package whatsmiau
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/verbeux-ai/whatsmiau/models"
+	"go.uber.org/zap"
+)
+
+const streamBufferSize = 256
+
+// EventStream receives the events of an instance, used by the websocket and SSE endpoints
+// as an alternative to webhooks
+type EventStream struct {
+	C chan []byte
+
+	instanceID string
+	events     []string
+}
+
+type eventStreams struct {
+	mu      sync.RWMutex
+	streams map[string]map[*EventStream]struct{}
+}
+
+func newEventStreams() *eventStreams {
+	return &eventStreams{streams: make(map[string]map[*EventStream]struct{})}
+}
+
+// Subscribe starts streaming the instance events matching the filter (same syntax as webhook events, empty means all)
+func (s *Whatsmiau) Subscribe(instanceID string, events []string) *EventStream {
+	stream := &EventStream{
+		C:          make(chan []byte, streamBufferSize),
+		instanceID: instanceID,
+		events:     events,
+	}
+
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+
+	if s.streams.streams[instanceID] == nil {
+		s.streams.streams[instanceID] = make(map[*EventStream]struct{})
+	}
+	s.streams.streams[instanceID][stream] = struct{}{}
+
+	return stream
+}
+
+func (s *Whatsmiau) Unsubscribe(stream *EventStream) {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+
+	delete(s.streams.streams[stream.instanceID], stream)
+	if len(s.streams.streams[stream.instanceID]) == 0 {
+		delete(s.streams.streams, stream.instanceID)
+	}
+}
+
+func (e *EventStream) wants(event string) bool {
+	return len(e.events) == 0 || matchEvents(e.events, event)
+}
+
+// streamEvents adds to the event map the events wanted by the open streams of the instance
+func (s *Whatsmiau) streamEvents(instance *models.Instance, eventMap map[string]bool) {
+	s.streams.mu.RLock()
+	defer s.streams.mu.RUnlock()
+
+	for stream := range s.streams.streams[instance.ID] {
+		for _, event := range knownEvents {
+			if stream.wants(event) {
+				eventMap[event] = true
+			}
+		}
+	}
+}
+
+// publish sends the event to the open streams of the instance. Slow streams lose events instead of
+// holding the emitter.
+func (s *Whatsmiau) publish(instanceID string, body any) {
+	s.streams.mu.RLock()
+	defer s.streams.mu.RUnlock()
+
+	streams := s.streams.streams[instanceID]
+	if len(streams) == 0 {
+		return
+	}
+
+	event := ""
+	if evt, ok := body.(wookEvent); ok {
+		event = evt.EventName().SubscriptionName()
+	}
+
+	var data []byte
+	for stream := range streams {
+		if event != "" && !stream.wants(event) {
+			continue
+		}
+
+		if data == nil {
+			result, err := json.Marshal(withoutBase64(body))
+			if err != nil {
+				zap.L().Error("failed to marshal event", zap.Error(err))
+				return
+			}
+			data = result
+		}
+
+		select {
+		case stream.C <- data:
+		default:
+			zap.L().Warn("event stream is full, dropping event", zap.String("instance", instanceID), zap.String("event", event))
+		}
+	}
+}
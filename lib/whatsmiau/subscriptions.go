@@ -0,0 +1,190 @@
+package whatsmiau
+
+import (
+	"path"
+	"strings"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+// wookSubscriptions maps the event sent on the payload to the name used on the webhook events list
+var wookSubscriptions = map[Wook]string{
+	WookMessagesUpsert:          "MESSAGES_UPSERT",
+	WookMessagesUpdate:          "MESSAGES_UPDATE",
+	WookContactsUpsert:          "CONTACTS_UPSERT",
+	WookConnectionUpdate:        "CONNECTION_UPDATE",
+	WookSendMessage:             "SEND_MESSAGE",
+	WookGroupsUpsert:            "GROUPS_UPSERT",
+	WookGroupsUpdate:            "GROUP_UPDATE",
+	WookGroupParticipantsUpdate: "GROUP_PARTICIPANTS_UPDATE",
+	WookNewsletterMessage:       "NEWSLETTER_MESSAGE",
+	WookStatusUpsert:            "STATUS_UPSERT",
+	WookWebhookTest:             "WEBHOOK_TEST",
+}
+
+// SubscriptionName returns the name used on the webhook events list (e.g. MESSAGES_UPSERT)
+func (w Wook) SubscriptionName() string {
+	return wookSubscriptions[w]
+}
+
+// knownEvents are the event names accepted on the webhook events list (same as Evolution)
+var knownEvents = []string{
+	"APPLICATION_STARTUP",
+	"QRCODE_UPDATED",
+	"MESSAGES_SET",
+	"MESSAGES_UPSERT",
+	"MESSAGES_UPDATE",
+	"MESSAGES_DELETE",
+	"SEND_MESSAGE",
+	"CONTACTS_SET",
+	"CONTACTS_UPSERT",
+	"CONTACTS_UPDATE",
+	"PRESENCE_UPDATE",
+	"CHATS_SET",
+	"CHATS_UPSERT",
+	"CHATS_UPDATE",
+	"CHATS_DELETE",
+	"GROUPS_UPSERT",
+	"GROUP_UPDATE",
+	"GROUP_PARTICIPANTS_UPDATE",
+	"CONNECTION_UPDATE",
+	"CALL",
+	"NEWSLETTER_MESSAGE",
+	"STATUS_UPSERT",
+}
+
+// subscriptions returns the enabled webhooks of the instance. When the instance publishes to a broker
+// only the main webhook is used, as the events filter (and base64 option) of the published events.
+func (s *Whatsmiau) subscriptions(instance *models.Instance) []models.InstanceWebhook {
+	if instanceSink(instance) != SinkWebhook {
+		return []models.InstanceWebhook{instance.Webhook}
+	}
+
+	return instance.Subscriptions()
+}
+
+// instanceEvents returns every event wanted by at least one webhook or event stream of the instance
+func (s *Whatsmiau) instanceEvents(instance *models.Instance) map[string]bool {
+	eventMap := make(map[string]bool)
+	webhooks := s.subscriptions(instance)
+	for _, event := range knownEvents {
+		for i := range webhooks {
+			if subscribed(&webhooks[i], event) {
+				eventMap[event] = true
+				break
+			}
+		}
+	}
+	s.streamEvents(instance, eventMap)
+
+	return eventMap
+}
+
+// DefaultWebhookEvents returns the events of DEFAULT_WEBHOOK_EVENTS
+func DefaultWebhookEvents() []string {
+	var result []string
+	for _, event := range strings.Split(env.Env.DefaultWebhookEvents, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
+// subscribed reports if the webhook wants the event, webhooks without events use DEFAULT_WEBHOOK_EVENTS
+func subscribed(webhook *models.InstanceWebhook, event string) bool {
+	if event == "" {
+		return false
+	}
+
+	// test pings reach every webhook, whatever its events
+	if event == WookWebhookTest.SubscriptionName() {
+		return true
+	}
+
+	patterns := webhook.Events
+	if len(patterns) == 0 {
+		patterns = DefaultWebhookEvents()
+	}
+
+	return matchEvents(patterns, event)
+}
+
+// matchEvents applies a subscription list to the event. Items can be "All", an event name, a glob
+// pattern (e.g. MESSAGES_*) or a negation of any of them (e.g. !CONTACTS_UPSERT). Negations always win,
+// and a list with only negations subscribes every other event.
+func matchEvents(patterns []string, event string) bool {
+	event = normalizeEventName(event)
+
+	matched, hasPositive, hasNegative := false, false, false
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = normalizeEventName(strings.TrimPrefix(pattern, "!"))
+		if pattern == "" {
+			continue
+		}
+
+		if negated {
+			hasNegative = true
+		} else {
+			hasPositive = true
+		}
+
+		if !matchEvent(pattern, event) {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+		matched = true
+	}
+
+	return matched || hasNegative && !hasPositive
+}
+
+func matchEvent(pattern, event string) bool {
+	if pattern == "ALL" || pattern == "*" || pattern == event {
+		return true
+	}
+
+	ok, err := path.Match(pattern, event)
+	return err == nil && ok
+}
+
+// normalizeEventName accepts messages.upsert, messages-upsert and MESSAGES_UPSERT as the same event
+func normalizeEventName(event string) string {
+	return strings.NewReplacer(".", "_", "-", "_").Replace(strings.ToUpper(strings.TrimSpace(event)))
+}
+
+// instanceWantsBase64 reports if some webhook of the instance receives the media as base64
+func (s *Whatsmiau) instanceWantsBase64(instance *models.Instance) bool {
+	for _, webhook := range s.subscriptions(instance) {
+		if webhook.Base64 != nil && *webhook.Base64 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutBase64 returns a copy of the message event without the media base64, the original is kept
+// for the webhooks that asked for it
+func withoutBase64(body any) any {
+	evt, ok := body.(*WookEvent[WookMessageData])
+	if !ok || evt.Data == nil || evt.Data.Message == nil || evt.Data.Message.Base64 == "" {
+		return body
+	}
+
+	message := *evt.Data.Message
+	message.Base64 = ""
+	data := *evt.Data
+	data.Message = &message
+	result := *evt
+	result.Data = &data
+
+	return &result
+}
@@ -0,0 +1,96 @@
+package whatsmiau
+
+import (
+	"testing"
+
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/models"
+)
+
+func TestNormalizeEventName(t *testing.T) {
+	tests := []struct {
+		event string
+		want  string
+	}{
+		{event: "MESSAGES_UPSERT", want: "MESSAGES_UPSERT"},
+		{event: "messages.upsert", want: "MESSAGES_UPSERT"},
+		{event: "messages-upsert", want: "MESSAGES_UPSERT"},
+		{event: "  group-participants.update ", want: "GROUP_PARTICIPANTS_UPDATE"},
+		{event: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			if got := normalizeEventName(tt.event); got != tt.want {
+				t.Errorf("normalizeEventName(%q) = %q, want %q", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		event    string
+		want     bool
+	}{
+		{name: "empty list", patterns: nil, event: "MESSAGES_UPSERT", want: false},
+		{name: "exact name", patterns: []string{"MESSAGES_UPSERT"}, event: "MESSAGES_UPSERT", want: true},
+		{name: "other name", patterns: []string{"MESSAGES_UPSERT"}, event: "MESSAGES_UPDATE", want: false},
+		{name: "dotted event", patterns: []string{"MESSAGES_UPSERT"}, event: "messages.upsert", want: true},
+		{name: "dotted pattern", patterns: []string{"messages.upsert"}, event: "MESSAGES_UPSERT", want: true},
+		{name: "all", patterns: []string{"All"}, event: "CONTACTS_UPSERT", want: true},
+		{name: "star", patterns: []string{"*"}, event: "CONTACTS_UPSERT", want: true},
+		{name: "glob prefix", patterns: []string{"MESSAGES_*"}, event: "MESSAGES_DELETE", want: true},
+		{name: "glob prefix miss", patterns: []string{"MESSAGES_*"}, event: "SEND_MESSAGE", want: false},
+		{name: "glob suffix", patterns: []string{"*_UPDATE"}, event: "GROUP_UPDATE", want: true},
+		{name: "lowercase glob", patterns: []string{"messages.*"}, event: "MESSAGES_UPDATE", want: true},
+		{name: "malformed glob", patterns: []string{"MESSAGES_["}, event: "MESSAGES_UPSERT", want: false},
+		{name: "negation wins over all", patterns: []string{"All", "!CONTACTS_UPSERT"}, event: "CONTACTS_UPSERT", want: false},
+		{name: "negation keeps the others", patterns: []string{"All", "!CONTACTS_UPSERT"}, event: "MESSAGES_UPSERT", want: true},
+		{name: "negation wins in any order", patterns: []string{"!MESSAGES_UPDATE", "MESSAGES_*"}, event: "MESSAGES_UPDATE", want: false},
+		{name: "negated glob", patterns: []string{"All", "!GROUP*"}, event: "GROUP_PARTICIPANTS_UPDATE", want: false},
+		{name: "only negations subscribe the rest", patterns: []string{"!CONTACTS_*"}, event: "MESSAGES_UPSERT", want: true},
+		{name: "only negations drop the match", patterns: []string{"!CONTACTS_*"}, event: "CONTACTS_SET", want: false},
+		{name: "positive list is not widened by a negation", patterns: []string{"MESSAGES_UPSERT", "!CALL"}, event: "CONTACTS_UPSERT", want: false},
+		{name: "blank items are ignored", patterns: []string{" ", "!", " MESSAGES_UPSERT "}, event: "MESSAGES_UPSERT", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchEvents(tt.patterns, tt.event); got != tt.want {
+				t.Errorf("matchEvents(%q, %q) = %t, want %t", tt.patterns, tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribed(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.DefaultWebhookEvents = "MESSAGES_UPSERT, CONNECTION_UPDATE"
+
+	tests := []struct {
+		name   string
+		events []string
+		event  string
+		want   bool
+	}{
+		{name: "empty event", events: []string{"All"}, event: "", want: false},
+		{name: "webhook events", events: []string{"MESSAGES_UPDATE"}, event: "MESSAGES_UPDATE", want: true},
+		{name: "webhook events replace the defaults", events: []string{"MESSAGES_UPDATE"}, event: "MESSAGES_UPSERT", want: false},
+		{name: "defaults without events", events: nil, event: "CONNECTION_UPDATE", want: true},
+		{name: "defaults miss", events: nil, event: "MESSAGES_UPDATE", want: false},
+		{name: "test ping ignores the events", events: []string{"!WEBHOOK_TEST"}, event: "WEBHOOK_TEST", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &models.InstanceWebhook{Events: tt.events}
+			if got := subscribed(webhook, tt.event); got != tt.want {
+				t.Errorf("subscribed(%q, %q) = %t, want %t", tt.events, tt.event, got, tt.want)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/verbeux-ai/whatsmiau/lib/storage/gcs"
 	"github.com/verbeux-ai/whatsmiau/models"
 	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/repositories/messages"
+	"github.com/verbeux-ai/whatsmiau/repositories/webhooks"
 	"github.com/verbeux-ai/whatsmiau/services"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store/sqlstore"
@@ -33,10 +35,24 @@ type Whatsmiau struct {
 	instanceCache    *cache.Cache // Changed from xsync.Map to go-cache for better performance
 	lockConnection   *xsync.Map[string, *sync.Mutex]
 	alwaysOnlineIDs  *xsync.Map[string, bool] // Track instances with AlwaysOnline enabled
-	emitter          chan emitter
+	communities      *xsync.Map[string, bool] // community jids seen on group metadata
+	pictures         *cache.Cache             // profile pictures and business profiles, see picture.go
+	emitter          chan *models.WebhookDelivery
+	outbox           interfaces.WebhookOutbox
+	deliveryLog      interfaces.WebhookDeliveryLog
+	statuses         interfaces.MessageStatusRepository
+	shards           []chan *models.WebhookDelivery
+	inflight         *xsync.Map[string, bool] // outbox stream ids waiting on a delivery worker
+	deferred         map[string]bool          // partitions held back by a full worker, only used by the outbox consumer
+	deferredIDs      map[string]bool          // outbox stream ids left pending on deferred partitions, only used by the outbox consumer
+	spill            *spillQueue
+	streams          *eventStreams
+	sinks            map[string]interfaces.EventSink
+	breakers         *circuitBreakers
 	httpClient       *http.Client
 	fileStorage      interfaces.Storage
 	handlerSemaphore chan struct{}
+	replySemaphore   chan struct{} // bounds the webhook reply actions running at the same time
 }
 
 var instance *Whatsmiau
@@ -139,16 +155,29 @@ func LoadMiau(ctx context.Context, container *sqlstore.Container) {
 		observerRunning: xsync.NewMap[string, bool](),
 		lockConnection:  xsync.NewMap[string, *sync.Mutex](),
 		alwaysOnlineIDs: xsync.NewMap[string, bool](), // Track AlwaysOnline instances
-		emitter:         make(chan emitter, env.Env.EmitterBufferSize),
+		communities:     xsync.NewMap[string, bool](),
+		pictures:        cache.New(env.Env.ProfileCacheTTL, 2*env.Env.ProfileCacheTTL),
+		emitter:         make(chan *models.WebhookDelivery, env.Env.EmitterBufferSize),
+		outbox:          webhooks.NewRedisOutbox(services.Redis()),
+		deliveryLog:     webhooks.NewRedisDeliveryLog(services.Redis()),
+		statuses:        messages.NewRedisStatus(services.Redis()),
+		spill:           newSpillQueue(env.Env.EmitterSpillDir),
+		streams:         newEventStreams(),
+		sinks:           loadSinks(),
+		breakers:        newCircuitBreakers(),
 		httpClient: &http.Client{
 			Transport: transport,
 			Timeout:   time.Second * 30, // Timeout total da requisição
 		},
 		fileStorage:      storage,
 		handlerSemaphore: make(chan struct{}, env.Env.HandlerSemaphoreSize),
+		replySemaphore:   make(chan struct{}, max(env.Env.WebhookReplyWorkers, 1)),
 	}
 
 	go instance.startEmitter()
+	go instance.startOutboxConsumer()
+	go instance.startSpillDrainer()
+	go instance.startRetryQueue()
 	go instance.keepAlwaysOnlineManager() // Centralized AlwaysOnline manager with batching
 
 	clients.Range(func(id string, client *whatsmeow.Client) bool {
@@ -306,6 +335,7 @@ func (s *Whatsmiau) observeConnection(client *whatsmeow.Client, id string) {
 				}); err != nil {
 					zap.L().Error("failed to update instance after login", zap.Error(err))
 				}
+				s.ForgetInstance(id)
 
 				// Registrar no manager centralizado se AlwaysOnline está ativo
 				if instanceFound := s.getInstance(id); instanceFound != nil && instanceFound.AlwaysOnline {
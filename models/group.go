@@ -0,0 +1,40 @@
+package models
+
+// GroupInfo is the group metadata in the same format of Evolution
+type GroupInfo struct {
+	ID                  string             `json:"id"`
+	Subject             string             `json:"subject"`
+	SubjectOwner        string             `json:"subjectOwner,omitempty"`
+	SubjectTime         int64              `json:"subjectTime,omitempty"`
+	PictureURL          string             `json:"pictureUrl,omitempty"`
+	Size                int                `json:"size"`
+	Creation            int64              `json:"creation,omitempty"`
+	Owner               string             `json:"owner,omitempty"`
+	Desc                string             `json:"desc,omitempty"`
+	DescID              string             `json:"descId,omitempty"`
+	Restrict            bool               `json:"restrict"`
+	Announce            bool               `json:"announce"`
+	IsCommunity         bool               `json:"isCommunity"`
+	IsCommunityAnnounce bool               `json:"isCommunityAnnounce"`    // announcement group of a community
+	LinkedParent        string             `json:"linkedParent,omitempty"` // community of the group
+	Participants        []GroupParticipant `json:"participants,omitempty"`
+}
+
+// CommunitySubGroup is a group linked to a community
+type CommunitySubGroup struct {
+	ID                  string `json:"id"`
+	Subject             string `json:"subject"`
+	IsCommunityAnnounce bool   `json:"isCommunityAnnounce"`
+}
+
+type GroupParticipant struct {
+	ID    string `json:"id"`
+	Lid   string `json:"lid,omitempty"`
+	Admin string `json:"admin,omitempty"` // admin, superadmin or empty
+}
+
+// GroupParticipantUpdate is the result of a participant change, status is the HTTP-like code sent by WhatsApp
+type GroupParticipantUpdate struct {
+	Status string `json:"status"`
+	Jid    string `json:"jid"`
+}
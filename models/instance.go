@@ -1,17 +1,21 @@
 package models
 
 type Instance struct {
-	ID                string          `json:"id,omitempty"`
-	RejectCall        bool            `json:"rejectCall,omitempty"`
-	MsgCall           string          `json:"msgCall,omitempty"`
-	GroupsIgnore      bool            `json:"groupsIgnore,omitempty"`
-	AlwaysOnline      bool            `json:"alwaysOnline,omitempty"`
-	ReadMessages      bool            `json:"readMessages,omitempty"`
-	ReadStatus        bool            `json:"readStatus,omitempty"`
-	SyncFullHistory   bool            `json:"syncFullHistory,omitempty"`
-	SyncRecentHistory bool            `json:"syncRecentHistory,omitempty"`
-	RemoteJID         string          `json:"remoteJID,omitempty"`
-	Webhook           InstanceWebhook `json:"webhook,omitempty"`
+	ID                string            `json:"id,omitempty"`
+	RejectCall        bool              `json:"rejectCall,omitempty"`
+	MsgCall           string            `json:"msgCall,omitempty"`
+	GroupsIgnore      bool              `json:"groupsIgnore,omitempty"`
+	AlwaysOnline      bool              `json:"alwaysOnline,omitempty"`
+	ReadMessages      bool              `json:"readMessages,omitempty"`
+	ReadStatus        bool              `json:"readStatus,omitempty"`    // views the statuses of contacts as they arrive
+	ReceiveStatus     *bool             `json:"receiveStatus,omitempty"` // emits the statuses of contacts as status.upsert
+	SyncFullHistory   bool              `json:"syncFullHistory,omitempty"`
+	SyncRecentHistory bool              `json:"syncRecentHistory,omitempty"`
+	RemoteJID         string            `json:"remoteJID,omitempty"`
+	Webhook           InstanceWebhook   `json:"webhook,omitempty"`
+	Webhooks          []InstanceWebhook `json:"webhooks,omitempty"`       // extra destinations, each one with its own events
+	Sink              string            `json:"sink,omitempty"`           // webhook, nats or amqp, empty uses EVENT_SINK
+	WebhookReplies    *bool             `json:"webhookReplies,omitempty"` // runs the actions returned on the webhook response
 	InstanceProxy
 }
 
@@ -24,9 +28,49 @@ type InstanceProxy struct {
 }
 
 type InstanceWebhook struct {
+	ID       string            `json:"id,omitempty"`
+	Enabled  *bool             `json:"enabled,omitempty"` // nil means enabled
 	Url      string            `json:"url,omitempty"`
 	ByEvents *bool             `json:"byEvents,omitempty"`
 	Base64   *bool             `json:"base64,omitempty"`
 	Headers  map[string]string `json:"headers,omitempty"`
 	Events   []string          `json:"events,omitempty"`
+	Secret   string            `json:"secret,omitempty"`  // used to sign deliveries (HMAC-SHA256)
+	Profile  string            `json:"profile,omitempty"` // evolution-v2 (default), evolution-v1 or compact
+	Batch    *WebhookBatch     `json:"batch,omitempty"`
+}
+
+func (w *InstanceWebhook) IsEnabled() bool {
+	return w.Enabled == nil || *w.Enabled
+}
+
+// FindWebhook returns the webhook with the given id, the main one when id is empty
+func (i *Instance) FindWebhook(id string) *InstanceWebhook {
+	if id == "" {
+		return &i.Webhook
+	}
+
+	for j := range i.Webhooks {
+		if i.Webhooks[j].ID == id {
+			return &i.Webhooks[j]
+		}
+	}
+
+	return nil
+}
+
+// Subscriptions returns every enabled webhook of the instance, starting by the main one
+func (i *Instance) Subscriptions() []InstanceWebhook {
+	result := make([]InstanceWebhook, 0, len(i.Webhooks)+1)
+	if i.Webhook.Url != "" && i.Webhook.IsEnabled() {
+		result = append(result, i.Webhook)
+	}
+
+	for _, webhook := range i.Webhooks {
+		if webhook.Url != "" && webhook.IsEnabled() {
+			result = append(result, webhook)
+		}
+	}
+
+	return result
 }
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// MessageStatusEntry is a status change of a message, in groups each participant has its own entries
+type MessageStatusEntry struct {
+	Status      string    `json:"status"` // SERVER_ACK, DELIVERY_ACK, READ, PLAYED or ERROR
+	Participant string    `json:"participant,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Newsletter is a WhatsApp channel
+type Newsletter struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	InviteCode   string    `json:"inviteCode,omitempty"`
+	Subscribers  int       `json:"subscribers"`
+	Verification string    `json:"verification,omitempty"`
+	State        string    `json:"state,omitempty"`
+	PictureURL   string    `json:"pictureUrl,omitempty"`
+	Role         string    `json:"role,omitempty"` // of the instance: owner, admin, subscriber or guest
+	Muted        bool      `json:"muted"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
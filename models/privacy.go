@@ -0,0 +1,19 @@
+package models
+
+// PrivacySettings are the privacy settings of a WhatsApp account, named as on Evolution. Empty fields are left unchanged.
+type PrivacySettings struct {
+	ReadReceipts string `json:"readreceipts,omitempty" validate:"omitempty,oneof=all none"`
+	Profile      string `json:"profile,omitempty" validate:"omitempty,oneof=all contacts contact_blacklist none"`
+	Status       string `json:"status,omitempty" validate:"omitempty,oneof=all contacts contact_blacklist none"`
+	Online       string `json:"online,omitempty" validate:"omitempty,oneof=all match_last_seen"`
+	Last         string `json:"last,omitempty" validate:"omitempty,oneof=all contacts contact_blacklist none"`
+	GroupAdd     string `json:"groupadd,omitempty" validate:"omitempty,oneof=all contacts contact_blacklist none"`
+	CallAdd      string `json:"calladd,omitempty" validate:"omitempty,oneof=all known"`
+}
+
+// PrivacyResult is the outcome of applying privacy settings on one instance
+type PrivacyResult struct {
+	Instance string           `json:"instance"`
+	Settings *PrivacySettings `json:"settings,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+// Profile is the public profile of a WhatsApp account
+type Profile struct {
+	Wuid       string `json:"wuid"`
+	Name       string `json:"name,omitempty"`
+	Picture    string `json:"picture,omitempty"`
+	PictureID  string `json:"pictureId,omitempty"`
+	Status     string `json:"status,omitempty"` // about text
+	IsBusiness bool   `json:"isBusiness"`
+}
+
+// BusinessProfile is the catalog profile of a WhatsApp Business account
+type BusinessProfile struct {
+	Wuid          string         `json:"wuid"`
+	IsBusiness    bool           `json:"isBusiness"`
+	Description   string         `json:"description,omitempty"`
+	Category      string         `json:"category,omitempty"`
+	Categories    []string       `json:"categories,omitempty"`
+	Email         string         `json:"email,omitempty"`
+	Website       []string       `json:"website,omitempty"`
+	Address       string         `json:"address,omitempty"`
+	BusinessHours *BusinessHours `json:"businessHours,omitempty"`
+}
+
+type BusinessHours struct {
+	TimeZone string               `json:"timezone,omitempty"`
+	Config   []BusinessHoursEntry `json:"config,omitempty"`
+}
+
+type BusinessHoursEntry struct {
+	DayOfWeek string `json:"dayOfWeek"`
+	Mode      string `json:"mode"` // specific_hours, open_24h or appointment_only
+	OpenTime  string `json:"openTime,omitempty"`
+	CloseTime string `json:"closeTime,omitempty"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDelivery is a webhook event persisted on the outbox until it is delivered or dead-lettered
+type WebhookDelivery struct {
+	ID         string            `json:"id"` // sent as X-Whatsmiau-Delivery, kept across retries and replays
+	StreamID   string            `json:"streamId,omitempty"`
+	InstanceID string            `json:"instanceId"`
+	WebhookID  string            `json:"webhookId,omitempty"` // empty for the main instance webhook
+	Event      string            `json:"event,omitempty"`
+	Partition  string            `json:"partition,omitempty"` // deliveries of the same partition keep their order
+	Sink       string            `json:"sink,omitempty"`      // empty for webhooks
+	URL        string            `json:"url,omitempty"`
+	Secret     string            `json:"-"` // resolved from the webhook at delivery time, never persisted
+	Headers    map[string]string `json:"-"` // resolved from the webhook at delivery time, never persisted
+	Payload    json.RawMessage   `json:"payload"`
+	Attempts   int               `json:"attempts"`
+	StatusCode int               `json:"statusCode,omitempty"` // of the last attempt
+	LatencyMs  int64             `json:"latencyMs,omitempty"`  // of the last attempt
+	Response   string            `json:"response,omitempty"`   // of the last attempt, truncated
+	LastError  string            `json:"lastError,omitempty"`
+	Reply      *WebhookReplyTo   `json:"reply,omitempty"` // set when the instance accepts reply actions
+	Batch      *WebhookBatch     `json:"batch,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	FailedAt   *time.Time        `json:"failedAt,omitempty"`
+}
+
+// WebhookBatch groups the events of a webhook, sent as a JSON array with up to Size items or after Interval ms
+type WebhookBatch struct {
+	Size     int  `json:"size" validate:"min=1,max=1000"`
+	Interval int  `json:"interval" validate:"min=0,max=60000"`
+	Gzip     bool `json:"gzip,omitempty"`
+}
+
+// WebhookReplyTo is the message the reply actions returned by the webhook refer to by default
+type WebhookReplyTo struct {
+	Chat        string `json:"chat"`
+	MessageID   string `json:"messageId,omitempty"`
+	Participant string `json:"participant,omitempty"`
+}
+
+// WebhookDeliveryLog is the outcome of a delivery, kept on a bounded list per instance to debug missing events
+type WebhookDeliveryLog struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhookId,omitempty"`
+	Event      string    `json:"event,omitempty"`
+	Sink       string    `json:"sink,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Response   string    `json:"response,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WebhookBreaker is the state of the circuit breaker of a webhook host
+type WebhookBreaker struct {
+	Host        string     `json:"host"`
+	State       string     `json:"state"` // closed, open or half-open
+	Failures    int        `json:"failures"`
+	OpenedAt    *time.Time `json:"openedAt,omitempty"`
+	Transitions int64      `json:"transitions"`
+}
+
+// WebhookBreakerSummary is the circuit breaker state of the webhook hosts, reported on /health
+type WebhookBreakerSummary struct {
+	Hosts       int              `json:"hosts"`
+	Open        int              `json:"open"`
+	HalfOpen    int              `json:"halfOpen"`
+	Transitions int64            `json:"transitions"` // state changes of every host since the start
+	Failing     []WebhookBreaker `json:"failing"`     // hosts not closed
+}
@@ -90,6 +90,27 @@ func (s *RedisInstance) Update(ctx context.Context, id string, toUpdate *models.
 	if toUpdate.Webhook.Events != nil && len(toUpdate.Webhook.Events) > 0 {
 		oldInstance.Webhook.Events = toUpdate.Webhook.Events
 	}
+	if toUpdate.Webhook.Secret != "" {
+		oldInstance.Webhook.Secret = toUpdate.Webhook.Secret
+	}
+	if toUpdate.Webhook.Profile != "" {
+		oldInstance.Webhook.Profile = toUpdate.Webhook.Profile
+	}
+	if toUpdate.Webhook.Batch != nil {
+		oldInstance.Webhook.Batch = toUpdate.Webhook.Batch
+	}
+	if toUpdate.ReceiveStatus != nil {
+		oldInstance.ReceiveStatus = toUpdate.ReceiveStatus
+	}
+	if toUpdate.WebhookReplies != nil {
+		oldInstance.WebhookReplies = toUpdate.WebhookReplies
+	}
+	if toUpdate.Sink != "" {
+		oldInstance.Sink = toUpdate.Sink
+	}
+	if toUpdate.Webhooks != nil {
+		oldInstance.Webhooks = toUpdate.Webhooks
+	}
 
 	data, err := json.Marshal(oldInstance)
 	if err != nil {
@@ -0,0 +1,9 @@
+package messages
+
+import "errors"
+
+// redis
+var (
+	ErrInstanceIDEmpty = errors.New("message instance id cannot be empty")
+	ErrMessageIDEmpty  = errors.New("message id cannot be empty")
+)
@@ -0,0 +1,88 @@
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+// These verify if RedisStatus follows message status interface pattern
+var _ interfaces.MessageStatusRepository = (*RedisStatus)(nil)
+
+// statusTimelineMaxLen bounds the timeline of a message, big groups send a receipt per participant
+const statusTimelineMaxLen = 1024
+
+// RedisStatus keeps the status timeline of each message for MESSAGE_STATUS_TTL, oldest first.
+// Every receipt writes the key of its message, so nothing is kept when the TTL is not positive.
+type RedisStatus struct {
+	db *redis.Client
+}
+
+func NewRedisStatus(client *redis.Client) *RedisStatus {
+	return &RedisStatus{
+		db: client,
+	}
+}
+
+func (s *RedisStatus) key(instanceID, messageID string) string {
+	return fmt.Sprintf("message_status_%s_%s", instanceID, messageID)
+}
+
+func (s *RedisStatus) Add(ctx context.Context, instanceID, messageID string, entry *models.MessageStatusEntry) error {
+	if instanceID == "" {
+		return ErrInstanceIDEmpty
+	}
+	if messageID == "" {
+		return ErrMessageIDEmpty
+	}
+
+	if env.Env.MessageStatusTTL <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// the expiration goes on the same transaction, a key is never left without it
+	key := s.key(instanceID, messageID)
+	_, err = s.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, data)
+		pipe.LTrim(ctx, key, -statusTimelineMaxLen, -1)
+		pipe.Expire(ctx, key, env.Env.MessageStatusTTL)
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisStatus) Timeline(ctx context.Context, instanceID, messageID string) ([]models.MessageStatusEntry, error) {
+	if instanceID == "" {
+		return nil, ErrInstanceIDEmpty
+	}
+	if messageID == "" {
+		return nil, ErrMessageIDEmpty
+	}
+
+	rawVals, err := s.db.LRange(ctx, s.key(instanceID, messageID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.MessageStatusEntry{}
+	for _, raw := range rawVals {
+		var entry models.MessageStatusEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+// These verify if RedisDeliveryLog follows delivery log interface pattern
+var _ interfaces.WebhookDeliveryLog = (*RedisDeliveryLog)(nil)
+
+// RedisDeliveryLog keeps the last WEBHOOK_DELIVERY_LOG_LEN delivery outcomes of each instance, newest first
+type RedisDeliveryLog struct {
+	db *redis.Client
+}
+
+func NewRedisDeliveryLog(client *redis.Client) *RedisDeliveryLog {
+	return &RedisDeliveryLog{
+		db: client,
+	}
+}
+
+func (s *RedisDeliveryLog) key(instanceID string) string {
+	return fmt.Sprintf("webhook_deliveries_%s", instanceID)
+}
+
+func (s *RedisDeliveryLog) Add(ctx context.Context, instanceID string, log *models.WebhookDeliveryLog) error {
+	if instanceID == "" {
+		return ErrInstanceIDEmpty
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+
+	key := s.key(instanceID)
+	_, err = s.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, env.Env.WebhookDeliveryLogLen-1)
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisDeliveryLog) List(ctx context.Context, instanceID string, limit int64) ([]models.WebhookDeliveryLog, error) {
+	if instanceID == "" {
+		return nil, ErrInstanceIDEmpty
+	}
+
+	rawVals, err := s.db.LRange(ctx, s.key(instanceID), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.WebhookDeliveryLog{}
+	for _, raw := range rawVals {
+		var log models.WebhookDeliveryLog
+		if err := json.Unmarshal([]byte(raw), &log); err != nil {
+			continue
+		}
+		result = append(result, log)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,9 @@
+package webhooks
+
+import "errors"
+
+// redis
+var (
+	ErrInstanceIDEmpty = errors.New("webhook delivery instance id cannot be empty")
+	ErrOutboxFull      = errors.New("webhook outbox is full")
+)
@@ -0,0 +1,406 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/verbeux-ai/whatsmiau/env"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"golang.org/x/net/context"
+)
+
+// These verify if RedisOutbox follows outbox interface pattern
+var _ interfaces.WebhookOutbox = (*RedisOutbox)(nil)
+
+const (
+	outboxStream     = "webhook_outbox"
+	outboxGroup      = "whatsmiau"
+	outboxRetryQueue = "webhook_retry"
+)
+
+// pushScript appends the delivery unless the outbox already holds WEBHOOK_OUTBOX_MAX_LEN entries.
+// Acked entries are deleted, so the stream length is the number of undelivered events and trimming
+// it would drop them.
+var pushScript = redis.NewScript(`
+local max = tonumber(ARGV[2])
+if max > 0 and redis.call('XLEN', KEYS[1]) >= max then
+	return 0
+end
+redis.call('XADD', KEYS[1], '*', 'data', ARGV[1])
+return 1
+`)
+
+// unparkScript moves a parked delivery back to the outbox only once, even with many replicas unparking.
+// The delivery stays parked while the outbox is full.
+var unparkScript = redis.NewScript(`
+local max = tonumber(ARGV[2])
+if max > 0 and redis.call('XLEN', KEYS[2]) >= max then
+	return 0
+end
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 1 then
+	redis.call('XADD', KEYS[2], '*', 'data', ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// RedisOutbox persists webhook deliveries on a Redis Stream consumed by a consumer group,
+// so events survive restarts and receiver outages. Deliveries that fail after the whole
+// retry schedule are moved to a dead-letter list per instance.
+type RedisOutbox struct {
+	db           *redis.Client
+	groupCreated atomic.Bool
+}
+
+func NewRedisOutbox(client *redis.Client) *RedisOutbox {
+	return &RedisOutbox{
+		db: client,
+	}
+}
+
+func (s *RedisOutbox) deadLetterKey(instanceID string) string {
+	return fmt.Sprintf("webhook_dead_%s", instanceID)
+}
+
+func (s *RedisOutbox) ensureGroup(ctx context.Context) error {
+	if s.groupCreated.Load() {
+		return nil
+	}
+
+	err := s.db.XGroupCreateMkStream(ctx, outboxStream, outboxGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	s.groupCreated.Store(true)
+	return nil
+}
+
+func (s *RedisOutbox) Push(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.InstanceID == "" {
+		return ErrInstanceIDEmpty
+	}
+
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	added, err := pushScript.Run(ctx, s.db, []string{outboxStream}, data, env.Env.WebhookOutboxMaxLen).Int()
+	if err != nil {
+		return err
+	}
+	if added == 0 {
+		return ErrOutboxFull
+	}
+
+	return nil
+}
+
+func (s *RedisOutbox) Read(ctx context.Context, consumer string, count int64, block time.Duration) ([]models.WebhookDelivery, error) {
+	if err := s.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	streams, err := s.db.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    outboxGroup,
+		Consumer: consumer,
+		Streams:  []string{outboxStream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.WebhookDelivery
+	for _, stream := range streams {
+		result = append(result, s.decode(ctx, stream.Messages)...)
+	}
+
+	return result, nil
+}
+
+func (s *RedisOutbox) ReadPending(ctx context.Context, consumer string, after string, count int64) ([]models.WebhookDelivery, error) {
+	if err := s.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	if after == "" {
+		after = "0"
+	}
+
+	// reading the group with an id instead of ">" returns the consumer pending history
+	streams, err := s.db.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    outboxGroup,
+		Consumer: consumer,
+		Streams:  []string{outboxStream, after},
+		Count:    count,
+		Block:    -1,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.WebhookDelivery
+	for _, stream := range streams {
+		result = append(result, s.decode(ctx, stream.Messages)...)
+	}
+
+	return result, nil
+}
+
+// Claim takes over deliveries left pending for longer than minIdle, e.g. after a crash in the middle of a delivery
+func (s *RedisOutbox) Claim(ctx context.Context, consumer string, minIdle time.Duration, count int64) ([]models.WebhookDelivery, error) {
+	if err := s.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		start  = "0-0"
+		result []models.WebhookDelivery
+	)
+
+	for {
+		messages, next, err := s.db.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   outboxStream,
+			Group:    outboxGroup,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    count,
+			Consumer: consumer,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, s.decode(ctx, messages)...)
+		if next == "0-0" || len(messages) == 0 || int64(len(result)) >= count {
+			break
+		}
+		start = next
+	}
+
+	return result, nil
+}
+
+// Touch resets the idle time of deliveries still held by the consumer, so other replicas do not claim them
+func (s *RedisOutbox) Touch(ctx context.Context, consumer string, streamIDs []string) error {
+	const chunk = 256
+	for start := 0; start < len(streamIDs); start += chunk {
+		end := min(start+chunk, len(streamIDs))
+
+		// JUSTID keeps the delivery counter, entries already acked are skipped by redis
+		err := s.db.XClaimJustID(ctx, &redis.XClaimArgs{
+			Stream:   outboxStream,
+			Group:    outboxGroup,
+			Consumer: consumer,
+			MinIdle:  0,
+			Messages: streamIDs[start:end],
+		}).Err()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *RedisOutbox) decode(ctx context.Context, messages []redis.XMessage) []models.WebhookDelivery {
+	var result []models.WebhookDelivery
+	for _, message := range messages {
+		raw, ok := message.Values["data"].(string)
+		var delivery models.WebhookDelivery
+		if !ok || json.Unmarshal([]byte(raw), &delivery) != nil {
+			// malformed entries would be claimed forever
+			s.remove(ctx, message.ID)
+			continue
+		}
+
+		delivery.StreamID = message.ID
+		result = append(result, delivery)
+	}
+
+	return result
+}
+
+func (s *RedisOutbox) remove(ctx context.Context, streamID string) error {
+	_, err := s.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, outboxStream, outboxGroup, streamID)
+		pipe.XDel(ctx, outboxStream, streamID)
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisOutbox) Ack(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.StreamID == "" {
+		return nil
+	}
+
+	return s.remove(ctx, delivery.StreamID)
+}
+
+func (s *RedisOutbox) DeadLetter(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.InstanceID == "" {
+		return ErrInstanceIDEmpty
+	}
+
+	now := time.Now()
+	dead := *delivery
+	dead.StreamID = ""
+	dead.FailedAt = &now
+
+	data, err := json.Marshal(dead)
+	if err != nil {
+		return err
+	}
+
+	key := s.deadLetterKey(delivery.InstanceID)
+	if _, err := s.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, env.Env.WebhookDeadLetterMaxLen-1)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return s.Ack(ctx, delivery)
+}
+
+func (s *RedisOutbox) ListDeadLetters(ctx context.Context, instanceID string, limit int64) ([]models.WebhookDelivery, error) {
+	if instanceID == "" {
+		return nil, ErrInstanceIDEmpty
+	}
+
+	rawVals, err := s.db.LRange(ctx, s.deadLetterKey(instanceID), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.WebhookDelivery{}
+	for _, raw := range rawVals {
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal([]byte(raw), &delivery); err != nil {
+			continue
+		}
+		result = append(result, delivery)
+	}
+
+	return result, nil
+}
+
+// ReplayDeadLetters pushes dead-lettered deliveries back to the outbox, all of them when ids is empty
+func (s *RedisOutbox) ReplayDeadLetters(ctx context.Context, instanceID string, ids []string) (int, error) {
+	if instanceID == "" {
+		return 0, ErrInstanceIDEmpty
+	}
+
+	key := s.deadLetterKey(instanceID)
+	rawVals, err := s.db.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	replayed := 0
+	// the list is newest first, replay from the oldest to keep the original order
+	for i := len(rawVals) - 1; i >= 0; i-- {
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal([]byte(rawVals[i]), &delivery); err != nil {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[delivery.ID] {
+			continue
+		}
+
+		delivery.Attempts = 0
+		delivery.LastError = ""
+		delivery.FailedAt = nil
+		if err := s.Push(ctx, &delivery); err != nil {
+			return replayed, err
+		}
+		if err := s.db.LRem(ctx, key, 1, rawVals[i]).Err(); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (s *RedisOutbox) PurgeDeadLetters(ctx context.Context, instanceID string) (int64, error) {
+	if instanceID == "" {
+		return 0, ErrInstanceIDEmpty
+	}
+
+	key := s.deadLetterKey(instanceID)
+	total, err := s.db.LLen(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return total, s.db.Del(ctx, key).Err()
+}
+
+func (s *RedisOutbox) Park(ctx context.Context, delivery *models.WebhookDelivery, until time.Time) error {
+	if delivery.InstanceID == "" {
+		return ErrInstanceIDEmpty
+	}
+
+	parked := *delivery
+	parked.StreamID = ""
+
+	data, err := json.Marshal(parked)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.ZAdd(ctx, outboxRetryQueue, &redis.Z{
+		Score:  float64(until.UnixMilli()),
+		Member: data,
+	}).Err(); err != nil {
+		return err
+	}
+
+	return s.Ack(ctx, delivery)
+}
+
+func (s *RedisOutbox) Unpark(ctx context.Context, now time.Time, count int64) (int, error) {
+	rawVals, err := s.db.ZRangeByScore(ctx, outboxRetryQueue, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   fmt.Sprintf("%d", now.UnixMilli()),
+		Count: count,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	unparked := 0
+	for _, raw := range rawVals {
+		moved, err := unparkScript.Run(ctx, s.db, []string{outboxRetryQueue, outboxStream}, raw, env.Env.WebhookOutboxMaxLen).Int()
+		if err != nil {
+			return unparked, err
+		}
+		unparked += moved
+	}
+
+	return unparked, nil
+}
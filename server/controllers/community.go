@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.uber.org/zap"
+)
+
+func (s *Group) CreateCommunity(ctx echo.Context) error {
+	var request dto.CreateCommunityRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	community, err := s.whatsmiau.CreateCommunity(ctx.Request().Context(), &whatsmiau.CreateCommunityRequest{
+		InstanceID:  request.InstanceID,
+		Subject:     request.Subject,
+		Description: request.Description,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.CreateCommunity failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to create community")
+	}
+
+	return ctx.JSON(http.StatusCreated, community)
+}
+
+func (s *Group) LinkCommunityGroup(ctx echo.Context) error {
+	return s.linkCommunityGroup(ctx, false)
+}
+
+func (s *Group) UnlinkCommunityGroup(ctx echo.Context) error {
+	return s.linkCommunityGroup(ctx, true)
+}
+
+func (s *Group) linkCommunityGroup(ctx echo.Context, unlink bool) error {
+	var request dto.LinkCommunityGroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	community, err := groupToJid(request.CommunityJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid community jid")
+	}
+
+	group, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	if err := s.whatsmiau.LinkCommunityGroup(ctx.Request().Context(), &whatsmiau.LinkCommunityGroupRequest{
+		InstanceID:   request.InstanceID,
+		CommunityJID: community,
+		GroupJID:     group,
+		Unlink:       unlink,
+	}); err != nil {
+		zap.L().Error("Whatsmiau.LinkCommunityGroup failed", zap.Bool("unlink", unlink), zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update community groups")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.LinkCommunityGroupResponse{
+		CommunityJID: community.String(),
+		GroupJID:     group.String(),
+		Linked:       !unlink,
+	})
+}
+
+func (s *Group) CommunitySubGroups(ctx echo.Context) error {
+	var request dto.CommunityRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	community, err := groupToJid(request.CommunityJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid community jid")
+	}
+
+	groups, err := s.whatsmiau.CommunitySubGroups(ctx.Request().Context(), request.InstanceID, *community)
+	if err != nil {
+		zap.L().Error("Whatsmiau.CommunitySubGroups failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list community groups")
+	}
+
+	return ctx.JSON(http.StatusOK, groups)
+}
+
+func (s *Group) CommunityAnnouncementGroup(ctx echo.Context) error {
+	var request dto.CommunityRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	community, err := groupToJid(request.CommunityJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid community jid")
+	}
+
+	group, err := s.whatsmiau.CommunityAnnouncementGroup(ctx.Request().Context(), request.InstanceID, *community)
+	if err != nil {
+		zap.L().Error("Whatsmiau.CommunityAnnouncementGroup failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to find community announcement group")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.CommunityAnnouncementGroupResponse{
+		CommunityJID: community.String(),
+		GroupJID:     group.String(),
+	})
+}
+
+func (s *Group) SendCommunityAnnouncement(ctx echo.Context) error {
+	var request dto.SendCommunityAnnouncementRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	community, err := groupToJid(request.CommunityJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid community jid")
+	}
+
+	res, err := s.whatsmiau.SendCommunityAnnouncement(ctx.Request().Context(), &whatsmiau.SendCommunityAnnouncementRequest{
+		InstanceID:   request.InstanceID,
+		CommunityJID: community,
+		Text:         request.Text,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.SendCommunityAnnouncement failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to send community announcement")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.SendTextResponse{
+		Key: dto.MessageResponseKey{
+			RemoteJid: res.GroupJID.String(),
+			FromMe:    true,
+			Id:        res.ID,
+		},
+		Status:           "sent",
+		Message:          dto.SendTextResponseMessage{Conversation: request.Text},
+		MessageType:      "conversation",
+		MessageTimestamp: int(res.CreatedAt.Unix()),
+		InstanceId:       request.InstanceID,
+	})
+}
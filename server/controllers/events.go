@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const streamKeepAlive = 30 * time.Second
+
+type Events struct {
+	repo      interfaces.InstanceRepository
+	whatsmiau *whatsmiau.Whatsmiau
+}
+
+func NewEvents(repository interfaces.InstanceRepository, whatsmiau *whatsmiau.Whatsmiau) *Events {
+	return &Events{
+		repo:      repository,
+		whatsmiau: whatsmiau,
+	}
+}
+
+// Stream sends the instance events through a websocket, or through server-sent events when the client accepts text/event-stream
+func (s *Events) Stream(ctx echo.Context) error {
+	var request dto.StreamEventsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	result, err := s.repo.List(ctx.Request().Context(), request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if len(result) == 0 {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	var events []string
+	for _, event := range strings.Split(request.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+
+	stream := s.whatsmiau.Subscribe(request.ID, events)
+	defer s.whatsmiau.Unsubscribe(stream)
+
+	if strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), "text/event-stream") {
+		return s.streamSSE(ctx, stream)
+	}
+
+	return s.streamWebsocket(ctx, stream)
+}
+
+func (s *Events) streamWebsocket(ctx echo.Context, stream *whatsmiau.EventStream) error {
+	conn, err := websocket.Accept(ctx.Response(), ctx.Request(), &websocket.AcceptOptions{
+		InsecureSkipVerify: true, // protected by the apikey
+	})
+	if err != nil {
+		zap.L().Error("failed to accept websocket", zap.Error(err))
+		return nil
+	}
+	defer conn.CloseNow()
+
+	// the client does not send messages, reading only handles close and ping frames
+	c := conn.CloseRead(ctx.Request().Context())
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case <-ticker.C:
+			if err := withTimeout(c, conn.Ping); err != nil {
+				return nil
+			}
+		case data := <-stream.C:
+			if err := withTimeout(c, func(wc context.Context) error {
+				return conn.Write(wc, websocket.MessageText, data)
+			}); err != nil {
+				zap.L().Debug("failed to write on websocket", zap.Error(err))
+				return nil
+			}
+		}
+	}
+}
+
+func (s *Events) streamSSE(ctx echo.Context, stream *whatsmiau.EventStream) error {
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	c := ctx.Request().Context()
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case data := <-stream.C:
+			if _, err := fmt.Fprintf(res, "data: %s\n\n", data); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+func withTimeout(ctx context.Context, fn func(context.Context) error) error {
+	c, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return fn(c)
+}
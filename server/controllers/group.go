@@ -0,0 +1,412 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.uber.org/zap"
+)
+
+const groupInvitePrefix = "https://chat.whatsapp.com/"
+
+type Group struct {
+	repo      interfaces.InstanceRepository
+	whatsmiau *whatsmiau.Whatsmiau
+}
+
+func NewGroups(repository interfaces.InstanceRepository, whatsmiau *whatsmiau.Whatsmiau) *Group {
+	return &Group{
+		repo:      repository,
+		whatsmiau: whatsmiau,
+	}
+}
+
+func (s *Group) Create(ctx echo.Context) error {
+	var request dto.CreateGroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	participants, err := numbersToJids(request.Participants)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid participant number format")
+	}
+
+	group, err := s.whatsmiau.CreateGroup(ctx.Request().Context(), &whatsmiau.CreateGroupRequest{
+		InstanceID:   request.InstanceID,
+		Subject:      request.Subject,
+		Description:  request.Description,
+		Participants: participants,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.CreateGroup failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to create group")
+	}
+
+	return ctx.JSON(http.StatusCreated, group)
+}
+
+func (s *Group) FetchAll(ctx echo.Context) error {
+	var request dto.FetchAllGroupsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	groups, err := s.whatsmiau.FetchAllGroups(ctx.Request().Context(), request.InstanceID, request.GetParticipants)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FetchAllGroups failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch groups")
+	}
+
+	return ctx.JSON(http.StatusOK, groups)
+}
+
+func (s *Group) FindInfo(ctx echo.Context) error {
+	var request dto.GroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	group, err := s.whatsmiau.FindGroupInfo(ctx.Request().Context(), request.InstanceID, *jid)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FindGroupInfo failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to find group info")
+	}
+
+	return ctx.JSON(http.StatusOK, group)
+}
+
+func (s *Group) Participants(ctx echo.Context) error {
+	var request dto.GroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	group, err := s.whatsmiau.FindGroupInfo(ctx.Request().Context(), request.InstanceID, *jid)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FindGroupInfo failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to find group participants")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.GroupParticipantsResponse{
+		Participants: group.Participants,
+	})
+}
+
+func (s *Group) UpdateSubject(ctx echo.Context) error {
+	var request dto.UpdateGroupSubjectRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	return s.update(ctx, request.InstanceID, request.GroupJID, &whatsmiau.UpdateGroupRequest{
+		Subject: &request.Subject,
+	})
+}
+
+func (s *Group) UpdateDescription(ctx echo.Context) error {
+	var request dto.UpdateGroupDescriptionRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	return s.update(ctx, request.InstanceID, request.GroupJID, &whatsmiau.UpdateGroupRequest{
+		Description: &request.Description,
+	})
+}
+
+func (s *Group) UpdatePicture(ctx echo.Context) error {
+	var request dto.UpdateGroupPictureRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	return s.update(ctx, request.InstanceID, request.GroupJID, &whatsmiau.UpdateGroupRequest{
+		Image: request.Image,
+	})
+}
+
+func (s *Group) update(ctx echo.Context, instanceID, groupJID string, data *whatsmiau.UpdateGroupRequest) error {
+	jid, err := groupToJid(groupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	data.InstanceID = instanceID
+	data.GroupJID = jid
+	if err := s.whatsmiau.UpdateGroup(ctx.Request().Context(), data); err != nil {
+		zap.L().Error("Whatsmiau.UpdateGroup failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update group")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateGroupResponse{
+		Update: "success",
+	})
+}
+
+func (s *Group) UpdateParticipants(ctx echo.Context) error {
+	var request dto.UpdateGroupParticipantsRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	participants, err := numbersToJids(request.Participants)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid participant number format")
+	}
+
+	result, err := s.whatsmiau.UpdateGroupParticipants(ctx.Request().Context(), &whatsmiau.UpdateGroupParticipantsRequest{
+		InstanceID:   request.InstanceID,
+		GroupJID:     jid,
+		Action:       request.Action,
+		Participants: participants,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.UpdateGroupParticipants failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update group participants")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateGroupParticipantsResponse{
+		UpdateParticipants: result,
+	})
+}
+
+func (s *Group) UpdateSetting(ctx echo.Context) error {
+	var request dto.UpdateGroupSettingRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	if err := s.whatsmiau.UpdateGroupSetting(ctx.Request().Context(), &whatsmiau.UpdateGroupSettingRequest{
+		InstanceID: request.InstanceID,
+		GroupJID:   jid,
+		Action:     request.Action,
+	}); err != nil {
+		zap.L().Error("Whatsmiau.UpdateGroupSetting failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update group setting")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateGroupSettingResponse{
+		UpdateSetting: true,
+	})
+}
+
+func (s *Group) Leave(ctx echo.Context) error {
+	var request dto.GroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	if err := s.whatsmiau.LeaveGroup(ctx.Request().Context(), request.InstanceID, *jid); err != nil {
+		zap.L().Error("Whatsmiau.LeaveGroup failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to leave group")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.LeaveGroupResponse{
+		GroupJID: jid.String(),
+		Leave:    true,
+	})
+}
+
+func (s *Group) InviteCode(ctx echo.Context) error {
+	var request dto.GroupRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	link, err := s.whatsmiau.GroupInviteLink(ctx.Request().Context(), request.InstanceID, *jid, false)
+	if err != nil {
+		zap.L().Error("Whatsmiau.GroupInviteLink failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to get group invite code")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.GroupInviteCodeResponse{
+		InviteURL:  link,
+		InviteCode: strings.TrimPrefix(link, groupInvitePrefix),
+	})
+}
+
+func (s *Group) RevokeInviteCode(ctx echo.Context) error {
+	var request dto.GroupRequest
+	if err := bindWithQuery(ctx, &request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	link, err := s.whatsmiau.GroupInviteLink(ctx.Request().Context(), request.InstanceID, *jid, true)
+	if err != nil {
+		zap.L().Error("Whatsmiau.GroupInviteLink failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to revoke group invite code")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.RevokeGroupInviteCodeResponse{
+		Revoked:    true,
+		InviteCode: strings.TrimPrefix(link, groupInvitePrefix),
+	})
+}
+
+func (s *Group) InviteInfo(ctx echo.Context) error {
+	var request dto.GroupInviteRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	group, err := s.whatsmiau.GroupInviteInfo(ctx.Request().Context(), request.InstanceID, request.InviteCode)
+	if err != nil {
+		zap.L().Error("Whatsmiau.GroupInviteInfo failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to get invite info")
+	}
+
+	return ctx.JSON(http.StatusOK, group)
+}
+
+func (s *Group) AcceptInviteCode(ctx echo.Context) error {
+	var request dto.GroupInviteRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := s.whatsmiau.JoinGroupWithLink(ctx.Request().Context(), request.InstanceID, request.InviteCode)
+	if err != nil {
+		zap.L().Error("Whatsmiau.JoinGroupWithLink failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to accept invite code")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.AcceptGroupInviteCodeResponse{
+		Accepted: true,
+		GroupJID: jid.String(),
+	})
+}
+
+func (s *Group) AcceptInviteMessage(ctx echo.Context) error {
+	var request dto.AcceptGroupInviteMessageRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := groupToJid(request.GroupJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid group jid")
+	}
+
+	inviter, err := numberToJid(request.Inviter)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid inviter number format")
+	}
+
+	if err := s.whatsmiau.AcceptGroupInvite(ctx.Request().Context(), &whatsmiau.AcceptGroupInviteRequest{
+		InstanceID: request.InstanceID,
+		GroupJID:   jid,
+		Inviter:    inviter,
+		Code:       request.InviteCode,
+		Expiration: request.InviteExpiration,
+	}); err != nil {
+		zap.L().Error("Whatsmiau.AcceptGroupInvite failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to accept group invite")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.AcceptGroupInviteCodeResponse{
+		Accepted: true,
+		GroupJID: jid.String(),
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/labstack/echo/v4"
 	"github.com/verbeux-ai/whatsmiau/models"
 	"go.mau.fi/whatsmeow/types"
 )
@@ -27,6 +28,68 @@ func numberToJid(number string) (*types.JID, error) {
 	return &jid, nil
 }
 
+// groupToJid accepts the group id with or without the @g.us suffix
+func groupToJid(group string) (*types.JID, error) {
+	if !strings.Contains(group, "@") {
+		group += "@" + types.GroupServer
+	}
+
+	jid, err := types.ParseJID(group)
+	if err != nil || jid.Server != types.GroupServer {
+		return nil, fmt.Errorf("invalid group jid")
+	}
+
+	return &jid, nil
+}
+
+func newsletterToJid(newsletter string) (*types.JID, error) {
+	if !strings.Contains(newsletter, "@") {
+		newsletter += "@" + types.NewsletterServer
+	}
+
+	jid, err := types.ParseJID(newsletter)
+	if err != nil || jid.Server != types.NewsletterServer {
+		return nil, fmt.Errorf("invalid newsletter jid")
+	}
+
+	return &jid, nil
+}
+
+// parseNewsletterInvite accepts the invite code or a whatsapp.com/channel link
+func parseNewsletterInvite(link string) string {
+	code := strings.TrimSpace(link)
+	if i := strings.Index(code, "/channel/"); i >= 0 {
+		code = code[i+len("/channel/"):]
+	}
+	if i := strings.IndexAny(code, "?#/"); i >= 0 {
+		code = code[:i]
+	}
+
+	return code
+}
+
+func numbersToJids(numbers []string) ([]types.JID, error) {
+	result := make([]types.JID, 0, len(numbers))
+	for _, number := range numbers {
+		jid, err := numberToJid(number)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", number, err)
+		}
+		result = append(result, *jid)
+	}
+
+	return result, nil
+}
+
+// bindWithQuery binds the request like ctx.Bind, also reading the query params on POST/PUT routes (Evolution sends groupJid on the query)
+func bindWithQuery(ctx echo.Context, request any) error {
+	if err := ctx.Bind(request); err != nil {
+		return err
+	}
+
+	return (&echo.DefaultBinder{}).BindQueryParams(ctx, request)
+}
+
 func parseProxyURL(proxyURL string) (*models.InstanceProxy, error) {
 	if !strings.Contains(proxyURL, "://") {
 		return nil, fmt.Errorf("invalid proxy url, missing scheme: %s", proxyURL)
@@ -13,6 +13,7 @@ import (
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/skip2/go-qrcode"
 	"github.com/verbeux-ai/whatsmiau/interfaces"
@@ -61,6 +62,32 @@ func (s *Instance) Create(ctx echo.Context) error {
 		}
 	}
 	request.RemoteJID = ""
+	switch request.Sink {
+	case "", whatsmiau.SinkWebhook, whatsmiau.SinkNats, whatsmiau.SinkAmqp:
+	default:
+		return utils.HTTPFail(ctx, http.StatusBadRequest, nil, "invalid sink, use webhook, nats or amqp")
+	}
+	if request.Webhook.Url == "" {
+		request.Webhook.Url = env.Env.DefaultWebhookURL
+	}
+	if len(request.Webhook.Events) == 0 {
+		request.Webhook.Events = whatsmiau.DefaultWebhookEvents()
+	}
+	if request.Webhook.ByEvents == nil {
+		request.Webhook.ByEvents = &[]bool{env.Env.DefaultWebhookByEvents}[0]
+	}
+	if request.Webhook.Secret == "" {
+		secret, err := whatsmiau.GenerateWebhookSecret()
+		if err != nil {
+			return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to generate webhook secret")
+		}
+		request.Webhook.Secret = secret
+	}
+	for i := range request.Webhooks {
+		if request.Webhooks[i].ID == "" {
+			request.Webhooks[i].ID = uuid.NewString()
+		}
+	}
 
 	if len(request.ProxyHost) <= 0 && len(env.Env.ProxyAddresses) > 0 {
 		rd := rand.IntN(len(env.Env.ProxyAddresses))
@@ -95,11 +122,37 @@ func (s *Instance) Update(ctx echo.Context) error {
 	}
 
 	c := ctx.Request().Context()
+	if request.Webhook.Secret == "" {
+		current, err := s.repo.List(c, request.ID)
+		if err != nil {
+			zap.L().Error("failed to list instances", zap.Error(err))
+			return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+		}
+
+		// instances created before webhook signing get their secret on the first update
+		if len(current) > 0 && current[0].Webhook.Secret == "" {
+			secret, err := whatsmiau.GenerateWebhookSecret()
+			if err != nil {
+				return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to generate webhook secret")
+			}
+			request.Webhook.Secret = secret
+		}
+	}
+
 	instance, err := s.repo.Update(c, request.ID, &models.Instance{
-		ID: request.ID,
+		ID:             request.ID,
+		Sink:           request.Sink,
+		WebhookReplies: request.WebhookReplies,
+		ReceiveStatus:  request.ReceiveStatus,
 		Webhook: models.InstanceWebhook{
-			Url:    request.Webhook.URL,
-			Base64: &[]bool{request.Webhook.Base64}[0],
+			Url:      request.Webhook.URL,
+			Base64:   &[]bool{request.Webhook.Base64}[0],
+			Secret:   request.Webhook.Secret,
+			ByEvents: request.Webhook.ByEvents,
+			Headers:  request.Webhook.Headers,
+			Events:   request.Webhook.Events,
+			Profile:  request.Webhook.Profile,
+			Batch:    request.Webhook.Batch,
 		},
 	})
 	if err != nil {
@@ -109,6 +162,7 @@ func (s *Instance) Update(ctx echo.Context) error {
 		zap.L().Error("failed to create instance", zap.Error(err))
 		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update instance")
 	}
+	s.whatsmiau.ForgetInstance(request.ID)
 
 	return ctx.JSON(http.StatusCreated, dto.UpdateInstanceResponse{
 		Instance: instance,
@@ -330,6 +384,7 @@ func (s *Instance) Delete(ctx echo.Context) error {
 		zap.L().Error("failed to delete instance", zap.Error(err))
 		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to delete instance")
 	}
+	s.whatsmiau.ForgetInstance(request.ID)
 
 	return ctx.JSON(http.StatusOK, dto.DeleteInstanceResponse{
 		Message: "instance deleted",
@@ -17,12 +17,14 @@ import (
 
 type Message struct {
 	repo      interfaces.InstanceRepository
+	statuses  interfaces.MessageStatusRepository
 	whatsmiau *whatsmiau.Whatsmiau
 }
 
-func NewMessages(repository interfaces.InstanceRepository, whatsmiau *whatsmiau.Whatsmiau) *Message {
+func NewMessages(repository interfaces.InstanceRepository, statuses interfaces.MessageStatusRepository, whatsmiau *whatsmiau.Whatsmiau) *Message {
 	return &Message{
 		repo:      repository,
+		statuses:  statuses,
 		whatsmiau: whatsmiau,
 	}
 }
@@ -404,6 +406,48 @@ func (s *Message) sendVideo(ctx echo.Context, request interface{}) error {
 	})
 }
 
+// SendStatus posts a status to the audience of the status privacy of the account
+func (s *Message) SendStatus(ctx echo.Context) error {
+	var request dto.SendStatusRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	res, err := s.whatsmiau.SendStatus(ctx.Request().Context(), &whatsmiau.SendStatusRequest{
+		InstanceID:      request.InstanceID,
+		Type:            request.Type,
+		Content:         request.Content,
+		Caption:         request.Caption,
+		BackgroundColor: request.BackgroundColor,
+		Font:            request.Font,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.SendStatus failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to send status")
+	}
+
+	messageType := request.Type + "Message"
+	if request.Type == "text" {
+		messageType = "extendedTextMessage"
+	}
+
+	return ctx.JSON(http.StatusOK, dto.SendStatusResponse{
+		Key: dto.MessageResponseKey{
+			RemoteJid: types.StatusBroadcastJID.String(),
+			FromMe:    true,
+			Id:        res.ID,
+		},
+		Status:           "sent",
+		MessageType:      messageType,
+		MessageTimestamp: int(res.CreatedAt.Unix()),
+		InstanceId:       request.InstanceID,
+	})
+}
+
 // SendMissedCall simula uma notificação de chamada perdida
 // AVISO: Este endpoint é EXPERIMENTAL e pode violar os Termos de Serviço do WhatsApp
 // Use apenas em ambientes de desenvolvimento/teste e por sua conta e risco
@@ -447,3 +491,44 @@ func (s *Message) SendMissedCall(ctx echo.Context) error {
 		InstanceId:       request.InstanceID,
 	})
 }
+
+func (s *Message) Status(ctx echo.Context) error {
+	var request dto.MessageStatusRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	timeline, err := s.statuses.Timeline(ctx.Request().Context(), request.InstanceID, request.MessageID)
+	if err != nil {
+		zap.L().Error("failed to get message status", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to get message status")
+	}
+	if len(timeline) == 0 {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "message status not found")
+	}
+
+	response := dto.MessageStatusResponse{
+		MessageID:    request.MessageID,
+		Participants: make(map[string]string),
+		Timeline:     timeline,
+	}
+	for _, entry := range timeline {
+		status := whatsmiau.WookMessageUpdateStatus(entry.Status)
+		if response.Status == "" || status.Rank() > whatsmiau.WookMessageUpdateStatus(response.Status).Rank() {
+			response.Status = entry.Status
+		}
+
+		if entry.Participant == "" {
+			continue
+		}
+		if current, ok := response.Participants[entry.Participant]; !ok || status.Rank() > whatsmiau.WookMessageUpdateStatus(current).Rank() {
+			response.Participants[entry.Participant] = entry.Status
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+)
+
+const newsletterMessagesCount = 20
+
+type Newsletter struct {
+	repo      interfaces.InstanceRepository
+	whatsmiau *whatsmiau.Whatsmiau
+}
+
+func NewNewsletters(repository interfaces.InstanceRepository, whatsmiau *whatsmiau.Whatsmiau) *Newsletter {
+	return &Newsletter{
+		repo:      repository,
+		whatsmiau: whatsmiau,
+	}
+}
+
+func (s *Newsletter) Create(ctx echo.Context) error {
+	var request dto.CreateNewsletterRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	newsletter, err := s.whatsmiau.CreateNewsletter(ctx.Request().Context(), &whatsmiau.CreateNewsletterRequest{
+		InstanceID:  request.InstanceID,
+		Name:        request.Name,
+		Description: request.Description,
+		Picture:     request.Picture,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.CreateNewsletter failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to create newsletter")
+	}
+
+	return ctx.JSON(http.StatusCreated, newsletter)
+}
+
+func (s *Newsletter) Fetch(ctx echo.Context) error {
+	var request dto.NewsletterInfoRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	var jid *types.JID
+	var inviteCode string
+	if request.NewsletterJID != "" {
+		var err error
+		jid, err = newsletterToJid(request.NewsletterJID)
+		if err != nil {
+			return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid newsletter jid")
+		}
+	} else {
+		inviteCode = parseNewsletterInvite(request.InviteCode)
+	}
+
+	newsletter, err := s.whatsmiau.NewsletterInfo(ctx.Request().Context(), request.InstanceID, jid, inviteCode)
+	if err != nil {
+		zap.L().Error("Whatsmiau.NewsletterInfo failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch newsletter")
+	}
+
+	return ctx.JSON(http.StatusOK, newsletter)
+}
+
+func (s *Newsletter) List(ctx echo.Context) error {
+	var request dto.ListNewslettersRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	newsletters, err := s.whatsmiau.ListNewsletters(ctx.Request().Context(), request.InstanceID)
+	if err != nil {
+		zap.L().Error("Whatsmiau.ListNewsletters failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list newsletters")
+	}
+
+	return ctx.JSON(http.StatusOK, newsletters)
+}
+
+func (s *Newsletter) Messages(ctx echo.Context) error {
+	var request dto.NewsletterMessagesRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := newsletterToJid(request.NewsletterJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid newsletter jid")
+	}
+
+	if request.Count == 0 {
+		request.Count = newsletterMessagesCount
+	}
+
+	messages, err := s.whatsmiau.NewsletterMessages(ctx.Request().Context(), request.InstanceID, *jid, request.Count, request.Before)
+	if err != nil {
+		zap.L().Error("Whatsmiau.NewsletterMessages failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch newsletter messages")
+	}
+
+	return ctx.JSON(http.StatusOK, messages)
+}
+
+func (s *Newsletter) Follow(ctx echo.Context) error {
+	return s.follow(ctx, true)
+}
+
+func (s *Newsletter) Unfollow(ctx echo.Context) error {
+	return s.follow(ctx, false)
+}
+
+func (s *Newsletter) follow(ctx echo.Context, follow bool) error {
+	var request dto.NewsletterRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := newsletterToJid(request.NewsletterJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid newsletter jid")
+	}
+
+	if err := s.whatsmiau.FollowNewsletter(ctx.Request().Context(), request.InstanceID, *jid, follow); err != nil {
+		zap.L().Error("Whatsmiau.FollowNewsletter failed", zap.Bool("follow", follow), zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update newsletter subscription")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.FollowNewsletterResponse{
+		NewsletterJID: jid.String(),
+		Following:     follow,
+	})
+}
+
+func (s *Newsletter) Mute(ctx echo.Context) error {
+	var request dto.MuteNewsletterRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := newsletterToJid(request.NewsletterJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid newsletter jid")
+	}
+
+	if err := s.whatsmiau.MuteNewsletter(ctx.Request().Context(), request.InstanceID, *jid, request.Mute); err != nil {
+		zap.L().Error("Whatsmiau.MuteNewsletter failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to mute newsletter")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.MuteNewsletterResponse{
+		NewsletterJID: jid.String(),
+		Muted:         request.Mute,
+	})
+}
+
+func (s *Newsletter) Send(ctx echo.Context) error {
+	var request dto.SendNewsletterRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := newsletterToJid(request.NewsletterJID)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid newsletter jid")
+	}
+
+	res, err := s.whatsmiau.SendNewsletter(ctx.Request().Context(), &whatsmiau.SendNewsletterRequest{
+		InstanceID:    request.InstanceID,
+		NewsletterJID: jid,
+		Text:          request.Text,
+		MediaType:     request.MediaType,
+		Media:         request.Media,
+		Caption:       request.Caption,
+		FileName:      request.FileName,
+		Mimetype:      request.Mimetype,
+	})
+	if err != nil {
+		zap.L().Error("Whatsmiau.SendNewsletter failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to send newsletter message")
+	}
+
+	messageType := "conversation"
+	if request.MediaType != "" {
+		messageType = request.MediaType + "Message"
+	}
+
+	return ctx.JSON(http.StatusOK, dto.SendNewsletterResponse{
+		NewsletterJID:    jid.String(),
+		MessageID:        res.ID,
+		ServerID:         res.ServerID,
+		MessageType:      messageType,
+		MessageTimestamp: int(res.CreatedAt.Unix()),
+		InstanceId:       request.InstanceID,
+	})
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.uber.org/zap"
+)
+
+var errEmptyPrivacySettings = errors.New("no privacy setting to change")
+
+func (s *Chat) FetchPrivacySettings(ctx echo.Context) error {
+	var request dto.FetchPrivacySettingsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	settings, err := s.whatsmiau.FetchPrivacySettings(ctx.Request().Context(), request.InstanceID)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FetchPrivacySettings failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch privacy settings")
+	}
+
+	return ctx.JSON(http.StatusOK, settings)
+}
+
+func (s *Chat) UpdatePrivacySettings(ctx echo.Context) error {
+	var request dto.UpdatePrivacySettingsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if request.PrivacySettings == (models.PrivacySettings{}) {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, errEmptyPrivacySettings, "invalid request body")
+	}
+
+	settings, err := s.whatsmiau.UpdatePrivacySettings(ctx.Request().Context(), request.InstanceID, &request.PrivacySettings)
+	if err != nil {
+		zap.L().Error("Whatsmiau.UpdatePrivacySettings failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update privacy settings")
+	}
+
+	return ctx.JSON(http.StatusOK, settings)
+}
+
+// ApplyPrivacy applies the same privacy settings on every connected instance, e.g. to enforce a fleet-wide policy
+func (s *Instance) ApplyPrivacy(ctx echo.Context) error {
+	var request dto.ApplyPrivacySettingsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if request.PrivacySettings == (models.PrivacySettings{}) {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, errEmptyPrivacySettings, "invalid request body")
+	}
+
+	results := s.whatsmiau.ApplyPrivacySettings(ctx.Request().Context(), &request.PrivacySettings)
+
+	response := dto.ApplyPrivacySettingsResponse{Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			response.Failed++
+		} else {
+			response.Applied++
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.mau.fi/whatsmeow/types"
+	"go.uber.org/zap"
+)
+
+func (s *Chat) FetchProfile(ctx echo.Context) error {
+	var request dto.FetchProfileRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	var jid *types.JID
+	if request.Number != "" {
+		var err error
+		jid, err = numberToJid(request.Number)
+		if err != nil {
+			return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid number format")
+		}
+	}
+
+	profile, err := s.whatsmiau.FetchProfile(ctx.Request().Context(), request.InstanceID, jid)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FetchProfile failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
+
+func (s *Chat) UpdateProfileName(ctx echo.Context) error {
+	var request dto.UpdateProfileNameRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if err := s.whatsmiau.UpdateProfileName(ctx.Request().Context(), request.InstanceID, request.Name); err != nil {
+		zap.L().Error("Whatsmiau.UpdateProfileName failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update profile name")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateProfileResponse{Status: "success"})
+}
+
+func (s *Chat) UpdateProfileStatus(ctx echo.Context) error {
+	var request dto.UpdateProfileStatusRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if err := s.whatsmiau.UpdateProfileStatus(ctx.Request().Context(), request.InstanceID, request.Status); err != nil {
+		zap.L().Error("Whatsmiau.UpdateProfileStatus failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update profile status")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateProfileResponse{Status: "success"})
+}
+
+func (s *Chat) UpdateProfilePicture(ctx echo.Context) error {
+	var request dto.UpdateProfilePictureRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	pictureID, err := s.whatsmiau.UpdateProfilePicture(ctx.Request().Context(), request.InstanceID, request.Picture)
+	if err != nil {
+		zap.L().Error("Whatsmiau.UpdateProfilePicture failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update profile picture")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateProfileResponse{
+		Status:    "success",
+		PictureID: pictureID,
+	})
+}
+
+func (s *Chat) RemoveProfilePicture(ctx echo.Context) error {
+	var request dto.RemoveProfilePictureRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if err := s.whatsmiau.RemoveProfilePicture(ctx.Request().Context(), request.InstanceID); err != nil {
+		zap.L().Error("Whatsmiau.RemoveProfilePicture failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to remove profile picture")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.UpdateProfileResponse{Status: "success"})
+}
+
+func (s *Chat) FetchProfilePictureUrl(ctx echo.Context) error {
+	var request dto.FetchProfilePictureRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := numberToJid(request.Number)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid number format")
+	}
+
+	picture, err := s.whatsmiau.FetchProfilePicture(ctx.Request().Context(), request.InstanceID, *jid, request.Preview, request.Base64)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FetchProfilePicture failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch profile picture")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.FetchProfilePictureResponse{
+		Wuid:              jid.String(),
+		ProfilePictureUrl: picture.URL,
+		PictureID:         picture.ID,
+		Base64:            picture.Base64,
+	})
+}
+
+func (s *Chat) FetchBusinessProfile(ctx echo.Context) error {
+	var request dto.FetchBusinessProfileRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	jid, err := numberToJid(request.Number)
+	if err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid number format")
+	}
+
+	profile, err := s.whatsmiau.FetchBusinessProfile(ctx.Request().Context(), request.InstanceID, *jid)
+	if err != nil {
+		zap.L().Error("Whatsmiau.FetchBusinessProfile failed", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to fetch business profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
 	"github.com/verbeux-ai/whatsmiau/utils"
 	"go.mau.fi/whatsmeow"
 )
@@ -27,11 +28,22 @@ func Root(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, jsonData)
 }
 
-// Health check endpoint for Docker
-func Health(ctx echo.Context) error {
+type Health struct {
+	whatsmiau *whatsmiau.Whatsmiau
+}
+
+func NewHealth(whatsmiau *whatsmiau.Whatsmiau) *Health {
+	return &Health{
+		whatsmiau: whatsmiau,
+	}
+}
+
+// Check is the health endpoint for Docker, it also reports the webhook circuit breakers
+func (s *Health) Check(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, map[string]any{
-		"status":  "healthy",
-		"service": "whatsmiau",
-		"time":    time.Now().Unix(),
+		"status":          "healthy",
+		"service":         "whatsmiau",
+		"time":            time.Now().Unix(),
+		"webhookBreakers": s.whatsmiau.CircuitBreakerSummary(),
 	})
 }
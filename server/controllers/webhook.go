@@ -0,0 +1,443 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/interfaces"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/models"
+	"github.com/verbeux-ai/whatsmiau/repositories/webhooks"
+	"github.com/verbeux-ai/whatsmiau/server/dto"
+	"github.com/verbeux-ai/whatsmiau/utils"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+type Webhook struct {
+	repo        interfaces.InstanceRepository
+	outbox      interfaces.WebhookOutbox
+	deliveryLog interfaces.WebhookDeliveryLog
+	whatsmiau   *whatsmiau.Whatsmiau
+}
+
+func NewWebhooks(repository interfaces.InstanceRepository, outbox interfaces.WebhookOutbox, deliveryLog interfaces.WebhookDeliveryLog, whatsmiau *whatsmiau.Whatsmiau) *Webhook {
+	return &Webhook{
+		repo:        repository,
+		outbox:      outbox,
+		deliveryLog: deliveryLog,
+		whatsmiau:   whatsmiau,
+	}
+}
+
+func (s *Webhook) ListDeliveries(ctx echo.Context) error {
+	var request dto.ListDeliveriesRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 100
+	}
+
+	c := ctx.Request().Context()
+	exists, err := s.instanceExists(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if !exists {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	result, err := s.deliveryLog.List(c, request.ID, request.Limit)
+	if err != nil {
+		zap.L().Error("failed to list webhook deliveries", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list webhook deliveries")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.ListDeliveriesResponse{
+		Count:      len(result),
+		Deliveries: result,
+	})
+}
+
+func (s *Webhook) Test(ctx echo.Context) error {
+	var request dto.TestWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	instance, err := s.getInstance(ctx.Request().Context(), request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	deliveries := s.whatsmiau.PingWebhooks(instance)
+	if len(deliveries) == 0 {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, nil, "instance has no enabled webhook")
+	}
+
+	return ctx.JSON(http.StatusAccepted, dto.TestWebhookResponse{
+		Message:    "ping queued, check the delivery log",
+		Deliveries: deliveries,
+	})
+}
+
+func (s *Webhook) ListDeadLetters(ctx echo.Context) error {
+	var request dto.ListDeadLettersRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 100
+	}
+
+	c := ctx.Request().Context()
+	exists, err := s.instanceExists(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if !exists {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	result, err := s.outbox.ListDeadLetters(c, request.ID, request.Limit)
+	if err != nil {
+		zap.L().Error("failed to list dead letters", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list dead letters")
+	}
+
+	for i := range result {
+		result[i].Secret = ""
+	}
+
+	return ctx.JSON(http.StatusOK, dto.ListDeadLettersResponse{
+		Count:       len(result),
+		DeadLetters: result,
+	})
+}
+
+func (s *Webhook) ReplayDeadLetters(ctx echo.Context) error {
+	var request dto.ReplayDeadLettersRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	c := ctx.Request().Context()
+	exists, err := s.instanceExists(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if !exists {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	replayed, err := s.outbox.ReplayDeadLetters(c, request.ID, request.IDs)
+	if errors.Is(err, webhooks.ErrOutboxFull) {
+		return utils.HTTPFail(ctx, http.StatusServiceUnavailable, err, "webhook outbox is full, try again later")
+	}
+	if err != nil {
+		zap.L().Error("failed to replay dead letters", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to replay dead letters")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.ReplayDeadLettersResponse{
+		Replayed: replayed,
+	})
+}
+
+func (s *Webhook) PurgeDeadLetters(ctx echo.Context) error {
+	var request dto.PurgeDeadLettersRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	c := ctx.Request().Context()
+	exists, err := s.instanceExists(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if !exists {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	purged, err := s.outbox.PurgeDeadLetters(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to purge dead letters", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to purge dead letters")
+	}
+
+	return ctx.JSON(http.StatusOK, dto.PurgeDeadLettersResponse{
+		Purged: purged,
+	})
+}
+
+func (s *Webhook) List(ctx echo.Context) error {
+	var request dto.ListWebhooksRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	instance, err := s.getInstance(ctx.Request().Context(), request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	webhooks := instance.Webhooks
+	if webhooks == nil {
+		webhooks = []models.InstanceWebhook{}
+	}
+
+	return ctx.JSON(http.StatusOK, dto.ListWebhooksResponse{
+		Webhooks: webhooks,
+	})
+}
+
+func (s *Webhook) Get(ctx echo.Context) error {
+	var request dto.GetWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	instance, err := s.getInstance(ctx.Request().Context(), request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	index := findWebhook(instance.Webhooks, request.WebhookID)
+	if index < 0 {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "webhook not found")
+	}
+
+	return ctx.JSON(http.StatusOK, instance.Webhooks[index])
+}
+
+func (s *Webhook) Create(ctx echo.Context) error {
+	var request dto.CreateWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	c := ctx.Request().Context()
+	instance, err := s.getInstance(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	if request.Secret == "" {
+		secret, err := whatsmiau.GenerateWebhookSecret()
+		if err != nil {
+			return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to generate webhook secret")
+		}
+		request.Secret = secret
+	}
+
+	webhook := models.InstanceWebhook{
+		ID:       uuid.NewString(),
+		Enabled:  request.Enabled,
+		Url:      request.URL,
+		ByEvents: request.ByEvents,
+		Base64:   request.Base64,
+		Headers:  request.Headers,
+		Events:   request.Events,
+		Secret:   request.Secret,
+		Profile:  request.Profile,
+		Batch:    request.Batch,
+	}
+
+	if _, err := s.repo.Update(c, request.ID, &models.Instance{
+		Webhooks: append(instance.Webhooks, webhook),
+	}); err != nil {
+		zap.L().Error("failed to update instance", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to create webhook")
+	}
+	s.whatsmiau.ForgetInstance(request.ID)
+
+	return ctx.JSON(http.StatusCreated, webhook)
+}
+
+func (s *Webhook) Update(ctx echo.Context) error {
+	var request dto.UpdateWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	c := ctx.Request().Context()
+	instance, err := s.getInstance(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	index := findWebhook(instance.Webhooks, request.WebhookID)
+	if index < 0 {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "webhook not found")
+	}
+
+	webhook := &instance.Webhooks[index]
+	webhook.Url = request.URL
+	webhook.Events = request.Events
+	webhook.Headers = request.Headers
+	webhook.Base64 = request.Base64
+	webhook.ByEvents = request.ByEvents
+	webhook.Enabled = request.Enabled
+	webhook.Profile = request.Profile
+	webhook.Batch = request.Batch
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+
+	if _, err := s.repo.Update(c, request.ID, &models.Instance{
+		Webhooks: instance.Webhooks,
+	}); err != nil {
+		zap.L().Error("failed to update instance", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to update webhook")
+	}
+	s.whatsmiau.ForgetInstance(request.ID)
+
+	return ctx.JSON(http.StatusOK, webhook)
+}
+
+func (s *Webhook) Delete(ctx echo.Context) error {
+	var request dto.DeleteWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusUnprocessableEntity, err, "failed to bind request body")
+	}
+
+	if err := validator.New().Struct(&request); err != nil {
+		return utils.HTTPFail(ctx, http.StatusBadRequest, err, "invalid request body")
+	}
+
+	c := ctx.Request().Context()
+	instance, err := s.getInstance(c, request.ID)
+	if err != nil {
+		zap.L().Error("failed to list instances", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to list instances")
+	}
+	if instance == nil {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "instance not found")
+	}
+
+	index := findWebhook(instance.Webhooks, request.WebhookID)
+	if index < 0 {
+		return utils.HTTPFail(ctx, http.StatusNotFound, nil, "webhook not found")
+	}
+
+	webhooks := make([]models.InstanceWebhook, 0, len(instance.Webhooks)-1)
+	webhooks = append(webhooks, instance.Webhooks[:index]...)
+	webhooks = append(webhooks, instance.Webhooks[index+1:]...)
+
+	if _, err := s.repo.Update(c, request.ID, &models.Instance{
+		Webhooks: webhooks,
+	}); err != nil {
+		zap.L().Error("failed to update instance", zap.Error(err))
+		return utils.HTTPFail(ctx, http.StatusInternalServerError, err, "failed to delete webhook")
+	}
+	s.whatsmiau.ForgetInstance(request.ID)
+
+	return ctx.JSON(http.StatusOK, dto.DeleteWebhookResponse{
+		Message: "webhook deleted",
+	})
+}
+
+// ListBreakers returns the circuit breaker state of every webhook host
+func (s *Webhook) ListBreakers(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, dto.ListBreakersResponse{
+		Breakers: s.whatsmiau.CircuitBreakers(),
+	})
+}
+
+func findWebhook(webhooks []models.InstanceWebhook, id string) int {
+	for i := range webhooks {
+		if webhooks[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *Webhook) getInstance(ctx context.Context, id string) (*models.Instance, error) {
+	result, err := s.repo.List(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &result[0], nil
+}
+
+func (s *Webhook) instanceExists(ctx context.Context, id string) (bool, error) {
+	result, err := s.repo.List(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
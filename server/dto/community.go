@@ -0,0 +1,35 @@
+package dto
+
+type CreateCommunityRequest struct {
+	InstanceID  string `param:"instance" validate:"required"`
+	Subject     string `json:"subject" validate:"required,max=100"`
+	Description string `json:"description,omitempty"`
+}
+
+type CommunityRequest struct {
+	InstanceID   string `param:"instance" validate:"required"`
+	CommunityJID string `query:"communityJid" validate:"required"`
+}
+
+type LinkCommunityGroupRequest struct {
+	InstanceID   string `param:"instance" validate:"required"`
+	CommunityJID string `json:"communityJid" validate:"required"`
+	GroupJID     string `json:"groupJid" validate:"required"`
+}
+
+type LinkCommunityGroupResponse struct {
+	CommunityJID string `json:"communityJid"`
+	GroupJID     string `json:"groupJid"`
+	Linked       bool   `json:"linked"`
+}
+
+type CommunityAnnouncementGroupResponse struct {
+	CommunityJID string `json:"communityJid"`
+	GroupJID     string `json:"groupJid"`
+}
+
+type SendCommunityAnnouncementRequest struct {
+	InstanceID   string `param:"instance" validate:"required"`
+	CommunityJID string `json:"communityJid" validate:"required"`
+	Text         string `json:"text" validate:"required"`
+}
@@ -0,0 +1,101 @@
+package dto
+
+import "github.com/verbeux-ai/whatsmiau/models"
+
+type CreateGroupRequest struct {
+	InstanceID   string   `param:"instance" validate:"required"`
+	Subject      string   `json:"subject" validate:"required,max=100"`
+	Description  string   `json:"description,omitempty"`
+	Participants []string `json:"participants" validate:"required,min=1"`
+}
+
+type GroupRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	GroupJID   string `query:"groupJid" validate:"required"`
+}
+
+type FetchAllGroupsRequest struct {
+	InstanceID      string `param:"instance" validate:"required"`
+	GetParticipants bool   `query:"getParticipants"`
+}
+
+type UpdateGroupSubjectRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	GroupJID   string `query:"groupJid" validate:"required"`
+	Subject    string `json:"subject" validate:"required,max=100"`
+}
+
+type UpdateGroupDescriptionRequest struct {
+	InstanceID  string `param:"instance" validate:"required"`
+	GroupJID    string `query:"groupJid" validate:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateGroupPictureRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	GroupJID   string `query:"groupJid" validate:"required"`
+	Image      string `json:"image" validate:"required"` // URL or base64 data URI
+}
+
+type UpdateGroupParticipantsRequest struct {
+	InstanceID   string   `param:"instance" validate:"required"`
+	GroupJID     string   `query:"groupJid" validate:"required"`
+	Action       string   `json:"action" validate:"required,oneof=add remove promote demote"`
+	Participants []string `json:"participants" validate:"required,min=1"`
+}
+
+type UpdateGroupSettingRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	GroupJID   string `query:"groupJid" validate:"required"`
+	Action     string `json:"action" validate:"required,oneof=announcement not_announcement locked unlocked"`
+}
+
+type GroupParticipantsResponse struct {
+	Participants []models.GroupParticipant `json:"participants"`
+}
+
+type UpdateGroupParticipantsResponse struct {
+	UpdateParticipants []models.GroupParticipantUpdate `json:"updateParticipants"`
+}
+
+type UpdateGroupResponse struct {
+	Update string `json:"update"`
+}
+
+type UpdateGroupSettingResponse struct {
+	UpdateSetting bool `json:"updateSetting"`
+}
+
+type GroupInviteCodeResponse struct {
+	InviteURL  string `json:"inviteUrl"`
+	InviteCode string `json:"inviteCode"`
+}
+
+type RevokeGroupInviteCodeResponse struct {
+	Revoked    bool   `json:"revoked"`
+	InviteCode string `json:"inviteCode"`
+}
+
+type LeaveGroupResponse struct {
+	GroupJID string `json:"groupJid"`
+	Leave    bool   `json:"leave"`
+}
+
+type GroupInviteRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	InviteCode string `query:"inviteCode" validate:"required"` // code or chat.whatsapp.com link
+}
+
+type AcceptGroupInviteCodeResponse struct {
+	Accepted bool   `json:"accepted"`
+	GroupJID string `json:"groupJid"`
+}
+
+// AcceptGroupInviteMessageRequest accepts a groupInviteMessage received in chat, inviter is who sent it
+type AcceptGroupInviteMessageRequest struct {
+	InstanceID       string `param:"instance" validate:"required"`
+	GroupJID         string `json:"groupJid" validate:"required"`
+	Inviter          string `json:"inviter" validate:"required"`
+	InviteCode       string `json:"inviteCode" validate:"required"`
+	InviteExpiration int64  `json:"inviteExpiration,string"`
+}
@@ -13,10 +13,19 @@ type CreateInstanceResponse struct {
 }
 
 type UpdateInstanceRequest struct {
-	ID      string `json:"id,omitempty" param:"id" validate:"required"`
-	Webhook struct {
-		Base64 bool   `json:"base64,omitempty"`
-		URL    string `json:"url,omitempty"`
+	ID             string `json:"id,omitempty" param:"id" validate:"required"`
+	Sink           string `json:"sink,omitempty" validate:"omitempty,oneof=webhook nats amqp"`
+	WebhookReplies *bool  `json:"webhookReplies,omitempty"` // runs the actions returned on the webhook response
+	ReceiveStatus  *bool  `json:"receiveStatus,omitempty"`  // emits the statuses of contacts as status.upsert
+	Webhook        struct {
+		Base64   bool                 `json:"base64,omitempty"`
+		URL      string               `json:"url,omitempty"`
+		Secret   string               `json:"secret,omitempty"`
+		ByEvents *bool                `json:"byEvents,omitempty"`
+		Headers  map[string]string    `json:"headers,omitempty"`
+		Events   []string             `json:"events,omitempty"`
+		Profile  string               `json:"profile,omitempty" validate:"omitempty,oneof=evolution-v2 evolution-v1 compact"`
+		Batch    *models.WebhookBatch `json:"batch,omitempty"`
 	} `json:"webhook,omitempty"`
 }
 
@@ -81,3 +90,8 @@ type LogoutInstanceRequest struct {
 type LogoutInstanceResponse struct {
 	Message string `json:"message,omitempty"`
 }
+
+type StreamEventsRequest struct {
+	ID     string `param:"id" validate:"required"`
+	Events string `query:"events"` // comma-separated, same syntax as webhook events
+}
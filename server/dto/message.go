@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/verbeux-ai/whatsmiau/models"
+
 type SendTextRequest struct {
 	InstanceID       string                `param:"instance" validate:"required"`
 	Number           string                `json:"number,omitempty" validate:"required"` // JID
@@ -219,6 +221,24 @@ type SendVideoResponse struct {
 	Source           string             `json:"source,omitempty"`
 }
 
+// SendStatusRequest posts a status to the audience of the status privacy of the account
+type SendStatusRequest struct {
+	InstanceID      string `param:"instance" validate:"required"`
+	Type            string `json:"type" validate:"required,oneof=text image video audio"`
+	Content         string `json:"content" validate:"required"` // text, or URL/base64 data URI of the media
+	Caption         string `json:"caption,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty" validate:"omitempty,hexcolor"`
+	Font            int    `json:"font,omitempty" validate:"min=0,max=10"`
+}
+
+type SendStatusResponse struct {
+	Key              MessageResponseKey `json:"key,omitempty"`
+	Status           string             `json:"status,omitempty"`
+	MessageType      string             `json:"messageType,omitempty"`
+	MessageTimestamp int                `json:"messageTimestamp,omitempty"`
+	InstanceId       string             `json:"instanceId,omitempty"`
+}
+
 // SendMissedCallRequest - Estrutura para simular uma chamada perdida
 // AVISO: Este recurso é EXPERIMENTAL e pode violar os Termos de Serviço do WhatsApp
 // Use apenas em ambientes de desenvolvimento/teste e por sua conta e risco
@@ -235,3 +255,15 @@ type SendMissedCallResponse struct {
 	MessageTimestamp int                `json:"messageTimestamp,omitempty"`
 	InstanceId       string             `json:"instanceId,omitempty"`
 }
+
+type MessageStatusRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	MessageID  string `param:"messageId" validate:"required"`
+}
+
+type MessageStatusResponse struct {
+	MessageID    string                      `json:"messageId"`
+	Status       string                      `json:"status"`
+	Participants map[string]string           `json:"participants,omitempty"` // latest status of each group participant
+	Timeline     []models.MessageStatusEntry `json:"timeline"`
+}
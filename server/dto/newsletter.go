@@ -0,0 +1,66 @@
+package dto
+
+type CreateNewsletterRequest struct {
+	InstanceID  string `param:"instance" validate:"required"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description,omitempty"`
+	Picture     string `json:"picture,omitempty"` // URL or base64 data URI
+}
+
+type NewsletterInfoRequest struct {
+	InstanceID    string `param:"instance" validate:"required"`
+	NewsletterJID string `query:"newsletterJid" validate:"required_without=InviteCode"`
+	InviteCode    string `query:"inviteCode" validate:"required_without=NewsletterJID"`
+}
+
+type ListNewslettersRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+}
+
+type NewsletterMessagesRequest struct {
+	InstanceID    string `param:"instance" validate:"required"`
+	NewsletterJID string `query:"newsletterJid" validate:"required"`
+	Count         int    `query:"count" validate:"omitempty,min=1,max=100"`
+	Before        int    `query:"before" validate:"omitempty,min=1"` // server id of the oldest message already fetched
+}
+
+type NewsletterRequest struct {
+	InstanceID    string `param:"instance" validate:"required"`
+	NewsletterJID string `json:"newsletterJid" validate:"required"`
+}
+
+type FollowNewsletterResponse struct {
+	NewsletterJID string `json:"newsletterJid"`
+	Following     bool   `json:"following"`
+}
+
+type MuteNewsletterRequest struct {
+	InstanceID    string `param:"instance" validate:"required"`
+	NewsletterJID string `json:"newsletterJid" validate:"required"`
+	Mute          bool   `json:"mute"`
+}
+
+type MuteNewsletterResponse struct {
+	NewsletterJID string `json:"newsletterJid"`
+	Muted         bool   `json:"muted"`
+}
+
+type SendNewsletterRequest struct {
+	InstanceID    string `param:"instance" validate:"required"`
+	NewsletterJID string `json:"newsletterJid" validate:"required"`
+	Text          string `json:"text" validate:"required_without=Media"`
+	MediaType     string `json:"mediatype" validate:"required_with=Media,omitempty,oneof=image video document"`
+	Media         string `json:"media" validate:"required_with=MediaType"` // URL or base64 data URI
+	Caption       string `json:"caption,omitempty"`
+	FileName      string `json:"fileName,omitempty"`
+	Mimetype      string `json:"mimetype,omitempty"`
+}
+
+type SendNewsletterResponse struct {
+	NewsletterJID    string `json:"newsletterJid"`
+	MessageID        string `json:"messageId"`
+	ServerID         int    `json:"serverId"`
+	MessageType      string `json:"messageType"`
+	MessageTimestamp int    `json:"messageTimestamp"`
+	InstanceId       string `json:"instanceId"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/verbeux-ai/whatsmiau/models"
+
+type FetchPrivacySettingsRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+}
+
+type UpdatePrivacySettingsRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	models.PrivacySettings
+}
+
+type ApplyPrivacySettingsRequest struct {
+	models.PrivacySettings
+}
+
+type ApplyPrivacySettingsResponse struct {
+	Applied int                    `json:"applied"`
+	Failed  int                    `json:"failed"`
+	Results []models.PrivacyResult `json:"results"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+type FetchProfileRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Number     string `json:"number,omitempty"` // empty for the connected number
+}
+
+type UpdateProfileNameRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Name       string `json:"name" validate:"required,max=25"`
+}
+
+type UpdateProfileStatusRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Status     string `json:"status" validate:"max=139"`
+}
+
+type UpdateProfilePictureRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Picture    string `json:"picture" validate:"required"` // URL or base64 data URI
+}
+
+type RemoveProfilePictureRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+}
+
+type UpdateProfileResponse struct {
+	Status    string `json:"status"`
+	PictureID string `json:"pictureId,omitempty"`
+}
+
+type FetchProfilePictureRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Number     string `json:"number" validate:"required"`
+	Preview    bool   `json:"preview,omitempty"` // 96x96 thumbnail instead of the full-size picture
+	Base64     bool   `json:"base64,omitempty"`  // includes the picture content
+}
+
+type FetchProfilePictureResponse struct {
+	Wuid              string `json:"wuid"`
+	ProfilePictureUrl string `json:"profilePictureUrl"`
+	PictureID         string `json:"pictureId,omitempty"`
+	Base64            string `json:"base64,omitempty"`
+}
+
+type FetchBusinessProfileRequest struct {
+	InstanceID string `param:"instance" validate:"required"`
+	Number     string `json:"number" validate:"required"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import "github.com/verbeux-ai/whatsmiau/models"
+
+type ListDeadLettersRequest struct {
+	ID    string `param:"id" validate:"required"`
+	Limit int64  `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type ListDeadLettersResponse struct {
+	Count       int                      `json:"count"`
+	DeadLetters []models.WebhookDelivery `json:"deadLetters"`
+}
+
+type ReplayDeadLettersRequest struct {
+	ID  string   `param:"id" validate:"required"`
+	IDs []string `json:"ids,omitempty"` // empty replays every dead-lettered event
+}
+
+type ReplayDeadLettersResponse struct {
+	Replayed int `json:"replayed"`
+}
+
+type PurgeDeadLettersRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type PurgeDeadLettersResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+type WebhookSubscription struct {
+	URL      string               `json:"url" validate:"required,url"`
+	Events   []string             `json:"events,omitempty"`
+	Headers  map[string]string    `json:"headers,omitempty"`
+	Base64   *bool                `json:"base64,omitempty"`
+	ByEvents *bool                `json:"byEvents,omitempty"`
+	Enabled  *bool                `json:"enabled,omitempty"`
+	Secret   string               `json:"secret,omitempty"`
+	Profile  string               `json:"profile,omitempty" validate:"omitempty,oneof=evolution-v2 evolution-v1 compact"`
+	Batch    *models.WebhookBatch `json:"batch,omitempty"`
+}
+
+type ListWebhooksRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []models.InstanceWebhook `json:"webhooks"`
+}
+
+type GetWebhookRequest struct {
+	ID        string `param:"id" validate:"required"`
+	WebhookID string `param:"webhookId" validate:"required"`
+}
+
+type CreateWebhookRequest struct {
+	ID string `param:"id" validate:"required"`
+	WebhookSubscription
+}
+
+type UpdateWebhookRequest struct {
+	ID        string `param:"id" validate:"required"`
+	WebhookID string `param:"webhookId" validate:"required"`
+	WebhookSubscription
+}
+
+type DeleteWebhookRequest struct {
+	ID        string `param:"id" validate:"required"`
+	WebhookID string `param:"webhookId" validate:"required"`
+}
+
+type DeleteWebhookResponse struct {
+	Message string `json:"message,omitempty"`
+}
+
+type ListDeliveriesRequest struct {
+	ID    string `param:"id" validate:"required"`
+	Limit int64  `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type ListDeliveriesResponse struct {
+	Count      int                         `json:"count"`
+	Deliveries []models.WebhookDeliveryLog `json:"deliveries"`
+}
+
+type TestWebhookRequest struct {
+	ID string `param:"id" validate:"required"`
+}
+
+type TestWebhookResponse struct {
+	Message    string   `json:"message"`
+	Deliveries []string `json:"deliveries"`
+}
+
+type ListBreakersResponse struct {
+	Breakers []models.WebhookBreaker `json:"breakers"`
+}
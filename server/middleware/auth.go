@@ -7,8 +7,30 @@ import (
 	"github.com/verbeux-ai/whatsmiau/env"
 )
 
+// EventStreamPath is authenticated by QueryAuth instead of Auth
+const EventStreamPath = "/v1/instance/:id/events"
+
+// Auth checks the apikey header. It must run after routing (Use), so the event stream can be left to QueryAuth.
 func Auth(ctx echo.Context, next echo.HandlerFunc) error {
+	if ctx.Path() == EventStreamPath && ctx.Request().Method == http.MethodGet {
+		return next(ctx)
+	}
+
+	return checkApikey(ctx, next, ctx.Request().Header.Get("apikey"))
+}
+
+// QueryAuth also accepts the apikey query parameter, browsers can not set headers on websocket and EventSource
+// connections. Use it only on the event stream, keys on URLs end up on access logs and Referer headers.
+func QueryAuth(ctx echo.Context, next echo.HandlerFunc) error {
 	gotApikey := ctx.Request().Header.Get("apikey")
+	if gotApikey == "" {
+		gotApikey = ctx.QueryParam("apikey")
+	}
+
+	return checkApikey(ctx, next, gotApikey)
+}
+
+func checkApikey(ctx echo.Context, next echo.HandlerFunc, gotApikey string) error {
 	if len(env.Env.ApiKey) == 0 {
 		return next(ctx)
 	}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/env"
+)
+
+func TestAuth(t *testing.T) {
+	oldEnv := env.Env
+	t.Cleanup(func() { env.Env = oldEnv })
+	env.Env.ApiKey = "secret-key"
+
+	ok := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+	app := echo.New()
+	app.Use(Simplify(Auth))
+	app.GET(EventStreamPath, ok, Simplify(QueryAuth))
+	app.POST(EventStreamPath, ok)
+	app.GET("/v1/instance/:id/status", ok)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header string
+		want   int
+	}{
+		{name: "header", method: http.MethodGet, target: "/v1/instance/a/status", header: "secret-key", want: http.StatusOK},
+		{name: "wrong header", method: http.MethodGet, target: "/v1/instance/a/status", header: "other", want: http.StatusUnauthorized},
+		{name: "missing key", method: http.MethodGet, target: "/v1/instance/a/status", want: http.StatusUnauthorized},
+		{name: "query is ignored on other routes", method: http.MethodGet, target: "/v1/instance/a/status?apikey=secret-key", want: http.StatusUnauthorized},
+		{name: "query on the event stream", method: http.MethodGet, target: "/v1/instance/a/events?apikey=secret-key", want: http.StatusOK},
+		{name: "header on the event stream", method: http.MethodGet, target: "/v1/instance/a/events", header: "secret-key", want: http.StatusOK},
+		{name: "wrong query on the event stream", method: http.MethodGet, target: "/v1/instance/a/events?apikey=other", want: http.StatusUnauthorized},
+		{name: "missing key on the event stream", method: http.MethodGet, target: "/v1/instance/a/events", want: http.StatusUnauthorized},
+		{name: "query on another method of the stream path", method: http.MethodPost, target: "/v1/instance/a/events?apikey=secret-key", want: http.StatusUnauthorized},
+		{name: "unknown route", method: http.MethodGet, target: "/v1/unknown?apikey=secret-key", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("apikey", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			app.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -28,4 +28,13 @@ func ChatEVO(group *echo.Group) {
 	group.POST("/whatsappNumbers/:instance", controller.NumberExists)
 	group.POST("/deleteChat/:instance", controller.DeleteChat)
 	group.POST("/archiveChat/:instance", controller.ArchiveChat)
+	group.POST("/fetchProfile/:instance", controller.FetchProfile)
+	group.POST("/updateProfileName/:instance", controller.UpdateProfileName)
+	group.POST("/updateProfileStatus/:instance", controller.UpdateProfileStatus)
+	group.POST("/updateProfilePicture/:instance", controller.UpdateProfilePicture)
+	group.DELETE("/removeProfilePicture/:instance", controller.RemoveProfilePicture)
+	group.POST("/fetchProfilePictureUrl/:instance", controller.FetchProfilePictureUrl)
+	group.POST("/fetchBusinessProfile/:instance", controller.FetchBusinessProfile)
+	group.GET("/fetchPrivacySettings/:instance", controller.FetchPrivacySettings)
+	group.POST("/updatePrivacySettings/:instance", controller.UpdatePrivacySettings)
 }
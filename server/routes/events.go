@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/server/controllers"
+	"github.com/verbeux-ai/whatsmiau/server/middleware"
+	"github.com/verbeux-ai/whatsmiau/services"
+)
+
+func Events(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewEvents(redisInstance, whatsmiau.Get())
+
+	group.GET("", controller.Stream, middleware.Simplify(middleware.QueryAuth))
+}
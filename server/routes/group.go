@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/server/controllers"
+	"github.com/verbeux-ai/whatsmiau/services"
+)
+
+func GroupEVO(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewGroups(redisInstance, whatsmiau.Get())
+
+	// Evolution API Compatibility (groupJid goes on the query)
+	group.POST("/create/:instance", controller.Create)
+	group.GET("/fetchAllGroups/:instance", controller.FetchAll)
+	group.GET("/findGroupInfos/:instance", controller.FindInfo)
+	group.GET("/participants/:instance", controller.Participants)
+	group.POST("/updateGroupSubject/:instance", controller.UpdateSubject)
+	group.POST("/updateGroupDescription/:instance", controller.UpdateDescription)
+	group.POST("/updateGroupPicture/:instance", controller.UpdatePicture)
+	group.POST("/updateParticipant/:instance", controller.UpdateParticipants)
+	group.POST("/updateSetting/:instance", controller.UpdateSetting)
+	group.GET("/inviteCode/:instance", controller.InviteCode)
+	group.POST("/revokeInviteCode/:instance", controller.RevokeInviteCode)
+	group.GET("/inviteInfo/:instance", controller.InviteInfo)
+	group.GET("/acceptInviteCode/:instance", controller.AcceptInviteCode)
+	group.POST("/acceptInviteMessage/:instance", controller.AcceptInviteMessage)
+	group.DELETE("/leaveGroup/:instance", controller.Leave)
+}
+
+func Community(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewGroups(redisInstance, whatsmiau.Get())
+
+	group.POST("/create/:instance", controller.CreateCommunity)
+	group.POST("/linkGroup/:instance", controller.LinkCommunityGroup)
+	group.POST("/unlinkGroup/:instance", controller.UnlinkCommunityGroup)
+	group.GET("/subGroups/:instance", controller.CommunitySubGroups)
+	group.GET("/announcementGroup/:instance", controller.CommunityAnnouncementGroup)
+	group.POST("/sendAnnouncement/:instance", controller.SendCommunityAnnouncement)
+}
@@ -6,7 +6,8 @@ import (
 )
 
 func Load(app *echo.Echo) {
-	app.Pre(middleware.Simplify(middleware.Auth))
+	// after routing, so Auth knows the route
+	app.Use(middleware.Simplify(middleware.Auth))
 
 	V1(app.Group("/v1"))
 }
@@ -16,7 +17,14 @@ func V1(group *echo.Group) {
 	Instance(group.Group("/instance"))
 	Message(group.Group("/instance/:instance/message"))
 	Chat(group.Group("/instance/:instance/chat"))
+	Webhook(group.Group("/instance/:id/webhook"))
+	Webhooks(group.Group("/instance/:id/webhooks"))
+	Events(group.Group("/instance/:id/events"))
+	WebhookHosts(group.Group("/webhook"))
 
 	ChatEVO(group.Group("/chat"))
 	MessageEVO(group.Group("/message"))
+	GroupEVO(group.Group("/group"))
+	Community(group.Group("/community"))
+	Newsletter(group.Group("/newsletter"))
 }
@@ -4,13 +4,15 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
 	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/repositories/messages"
 	"github.com/verbeux-ai/whatsmiau/server/controllers"
 	"github.com/verbeux-ai/whatsmiau/services"
 )
 
 func Message(group *echo.Group) {
 	redisInstance := instances.NewRedis(services.Redis())
-	controller := controllers.NewMessages(redisInstance, whatsmiau.Get())
+	redisStatus := messages.NewRedisStatus(services.Redis())
+	controller := controllers.NewMessages(redisInstance, redisStatus, whatsmiau.Get())
 
 	group.POST("/text", controller.SendText)
 	group.POST("/audio", controller.SendAudio)
@@ -18,11 +20,13 @@ func Message(group *echo.Group) {
 	group.POST("/image", controller.SendImage)
 	group.POST("/video", controller.SendVideo)
 	group.POST("/missedCall", controller.SendMissedCall)
+	group.GET("/:messageId/status", controller.Status)
 }
 
 func MessageEVO(group *echo.Group) {
 	redisInstance := instances.NewRedis(services.Redis())
-	controller := controllers.NewMessages(redisInstance, whatsmiau.Get())
+	redisStatus := messages.NewRedisStatus(services.Redis())
+	controller := controllers.NewMessages(redisInstance, redisStatus, whatsmiau.Get())
 
 	// Evolution API Compatibility (partially REST)
 	group.POST("/sendText/:instance", controller.SendText)
@@ -30,5 +34,6 @@ func MessageEVO(group *echo.Group) {
 	group.POST("/sendMedia/:instance", controller.SendMedia)
 	group.POST("/sendReaction/:instance", controller.SendReaction)
 	group.POST("/sendVideo/:instance", controller.SendVideo)
+	group.POST("/sendStatus/:instance", controller.SendStatus)
 	group.POST("/sendMissedCall/:instance", controller.SendMissedCall) // EXPERIMENTAL - Use at your own risk
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/server/controllers"
+	"github.com/verbeux-ai/whatsmiau/services"
+)
+
+func Newsletter(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewNewsletters(redisInstance, whatsmiau.Get())
+
+	group.POST("/create/:instance", controller.Create)
+	group.GET("/fetch/:instance", controller.Fetch)
+	group.GET("/list/:instance", controller.List)
+	group.GET("/messages/:instance", controller.Messages)
+	group.POST("/follow/:instance", controller.Follow)
+	group.POST("/unfollow/:instance", controller.Unfollow)
+	group.POST("/mute/:instance", controller.Mute)
+	group.POST("/send/:instance", controller.Send)
+}
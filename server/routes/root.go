@@ -2,10 +2,11 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
 	"github.com/verbeux-ai/whatsmiau/server/controllers"
 )
 
 func Root(group *echo.Group) {
 	group.GET("", controllers.Root)
-	group.GET("/health", controllers.Health)
+	group.GET("/health", controllers.NewHealth(whatsmiau.Get()).Check)
 }
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/verbeux-ai/whatsmiau/lib/whatsmiau"
+	"github.com/verbeux-ai/whatsmiau/repositories/instances"
+	"github.com/verbeux-ai/whatsmiau/repositories/webhooks"
+	"github.com/verbeux-ai/whatsmiau/server/controllers"
+	"github.com/verbeux-ai/whatsmiau/services"
+)
+
+func Webhooks(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewWebhooks(redisInstance, webhooks.NewRedisOutbox(services.Redis()), webhooks.NewRedisDeliveryLog(services.Redis()), whatsmiau.Get())
+
+	group.GET("", controller.List)
+	group.POST("", controller.Create)
+	group.GET("/:webhookId", controller.Get)
+	group.PUT("/:webhookId", controller.Update)
+	group.DELETE("/:webhookId", controller.Delete)
+}
+
+func Webhook(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewWebhooks(redisInstance, webhooks.NewRedisOutbox(services.Redis()), webhooks.NewRedisDeliveryLog(services.Redis()), whatsmiau.Get())
+
+	group.GET("/dead-letters", controller.ListDeadLetters)
+	group.POST("/dead-letters/replay", controller.ReplayDeadLetters)
+	group.DELETE("/dead-letters", controller.PurgeDeadLetters)
+	group.GET("/deliveries", controller.ListDeliveries)
+	group.POST("/test", controller.Test)
+}
+
+func WebhookHosts(group *echo.Group) {
+	redisInstance := instances.NewRedis(services.Redis())
+	controller := controllers.NewWebhooks(redisInstance, webhooks.NewRedisOutbox(services.Redis()), webhooks.NewRedisDeliveryLog(services.Redis()), whatsmiau.Get())
+
+	group.GET("/breakers", controller.ListBreakers)
+}